@@ -0,0 +1,264 @@
+package graphics
+
+import (
+	"time"
+)
+
+/*
+Sprite sheet animations, an animation steps the texture coordinates of a single rectangle in a render object
+through a set of frames. Animations are advanced on the main thread at the start of each render.
+*/
+
+// TexRect ... rectangle in texture pixel coordinates, from the top left of the texture
+type TexRect struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+type AnimationMode int
+
+const (
+	// AnimationLoop ... restart from the first frame after the last
+	AnimationLoop AnimationMode = iota
+	// AnimationPingPong ... play forwards then backwards
+	AnimationPingPong
+	// AnimationOnce ... stop on the last frame
+	AnimationOnce
+)
+
+type Frame struct {
+	Rect     TexRect
+	Duration time.Duration
+}
+
+/*
+OnComplete is called on the main thread at the end of every cycle, for AnimationOnce this is when the last frame has finished.
+As it is called on the main thread it must not enqueue jobs, use the non job methods instead.
+*/
+type Animation struct {
+	Frames     []Frame
+	Mode       AnimationMode
+	OnComplete func()
+
+	obj       *RenderObject
+	index     int
+	frame     int
+	direction int
+	elapsed   time.Duration
+	playing   bool
+}
+
+func CreateAnimation(mode AnimationMode, frames []Frame) *Animation {
+	return &Animation{
+		Frames: frames,
+		Mode:   mode,
+	}
+}
+
+// StripFrames ... create count frames laid out left to right from xTex, yTex, each lasting duration
+func StripFrames(xTex, yTex, widthTex, heightTex float32, count int, duration time.Duration) []Frame {
+	frames := make([]Frame, count)
+
+	for i := range frames {
+		frames[i] = Frame{
+			TexRect{xTex + float32(i)*widthTex, yTex, widthTex, heightTex},
+			duration,
+		}
+	}
+
+	return frames
+}
+
+// Animate ... attach an animation to the rectangle starting at index, replacing any existing animation on it
+func (obj *RenderObject) Animate(index int, anim *Animation) {
+	if len(anim.Frames) == 0 {
+		panic("Animation has no frames")
+	}
+
+	if obj.animations == nil {
+		obj.animations = make(map[int]*Animation)
+	}
+
+	anim.obj = obj
+	anim.index = index
+	anim.Reset()
+	anim.playing = true
+
+	obj.animations[index] = anim
+}
+
+func (obj *RenderObject) StopAnimation(index int) {
+	if anim, exists := obj.animations[index]; exists {
+		anim.playing = false
+		delete(obj.animations, index)
+	}
+}
+
+func (anim *Animation) Playing() bool {
+	return anim.playing
+}
+
+func (anim *Animation) Frame() int {
+	return anim.frame
+}
+
+// Reset ... return to the first frame, does not change if the animation is playing
+func (anim *Animation) Reset() {
+	anim.frame = 0
+	anim.direction = 1
+	anim.elapsed = 0
+	anim.apply()
+}
+
+func (anim *Animation) apply() {
+	if anim.obj == nil {
+		return
+	}
+
+	rect := anim.Frames[anim.frame].Rect
+	anim.obj.ModifyTexRect(anim.index, rect.X, rect.Y, rect.Width, rect.Height)
+}
+
+// advance ... step the animation forward by delta, returns true once the animation has finished
+func (anim *Animation) advance(delta time.Duration) bool {
+	if !anim.playing {
+		return true
+	}
+
+	anim.elapsed += delta
+	changed := false
+
+	for anim.playing && anim.elapsed >= anim.Frames[anim.frame].Duration {
+		anim.elapsed -= anim.Frames[anim.frame].Duration
+		changed = true
+		anim.step()
+
+		// Zero length frames would otherwise never leave this loop
+		if anim.Frames[anim.frame].Duration <= 0 {
+			anim.elapsed = 0
+			break
+		}
+	}
+
+	// OnComplete may have replaced the animation, whose first frame must not be overwritten
+	if changed && (anim.obj == nil || anim.obj.animations[anim.index] == anim) {
+		anim.apply()
+	}
+
+	return !anim.playing
+}
+
+func (anim *Animation) step() {
+	last := len(anim.Frames) - 1
+
+	switch anim.Mode {
+	case AnimationLoop:
+		if anim.frame == last {
+			anim.frame = 0
+			anim.complete()
+
+			return
+		}
+
+		anim.frame++
+	case AnimationPingPong:
+		if last == 0 {
+			anim.complete()
+
+			return
+		}
+
+		if anim.frame+anim.direction > last || anim.frame+anim.direction < 0 {
+			anim.direction = -anim.direction
+		}
+
+		anim.frame += anim.direction
+
+		if anim.frame == 0 {
+			anim.complete()
+		}
+	case AnimationOnce:
+		if anim.frame == last {
+			anim.playing = false
+			anim.complete()
+
+			return
+		}
+
+		anim.frame++
+	}
+}
+
+func (anim *Animation) complete() {
+	if anim.OnComplete != nil {
+		anim.OnComplete()
+	}
+}
+
+/*
+Animation updating, performed at the start of each render
+*/
+
+var lastAnimationUpdate time.Time
+
+func updateAnimations() {
+	now := time.Now()
+
+	if lastAnimationUpdate.IsZero() {
+		lastAnimationUpdate = now
+	}
+
+	delta := now.Sub(lastAnimationUpdate)
+	lastAnimationUpdate = now
+
+	for _, obj := range renderObjects {
+		obj.updateAnimations(delta)
+	}
+}
+
+func (obj *RenderObject) updateAnimations(delta time.Duration) {
+	for index, anim := range obj.animations {
+		// OnComplete may have started another animation at index, which must be kept
+		if anim.advance(delta) && obj.animations[index] == anim {
+			delete(obj.animations, index)
+		}
+	}
+}
+
+/*
+Animation jobs
+*/
+
+func callAnimate(job RenderObjectJob) {
+	params := job.params
+
+	job.obj.Animate(
+		*params[0].(*int),
+		params[1].(*Animation),
+	)
+}
+
+func callStopAnimation(job RenderObjectJob) {
+	job.obj.StopAnimation(*job.params[0].(*int))
+}
+
+// AnimateJob ... index is read when the job is performed so the result of AddSquareJob/AddRectJob can be passed directly
+func (obj *RenderObject) AnimateJob(index *int, anim *Animation) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index, anim},
+		nil,
+		callAnimate,
+	}
+}
+
+func (obj *RenderObject) StopAnimationJob(index *int) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index},
+		nil,
+		callStopAnimation,
+	}
+}
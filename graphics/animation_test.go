@@ -0,0 +1,76 @@
+package graphics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnimationAdvance(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		name          string
+		mode          AnimationMode
+		frames        int
+		deltas        []time.Duration
+		wantFrames    []int
+		wantCompletes int
+		wantPlaying   bool
+	}{
+		{"within frame", AnimationLoop, 3, []time.Duration{5 * ms, 4 * ms}, []int{0, 0}, 0, true},
+		{"step", AnimationLoop, 3, []time.Duration{10 * ms, 10 * ms}, []int{1, 2}, 0, true},
+		{"carry elapsed", AnimationLoop, 3, []time.Duration{15 * ms, 5 * ms}, []int{1, 2}, 0, true},
+		{"several frames at once", AnimationLoop, 4, []time.Duration{30 * ms}, []int{3}, 0, true},
+		{"loop", AnimationLoop, 3, []time.Duration{20 * ms, 10 * ms, 10 * ms}, []int{2, 0, 1}, 1, true},
+		{"loop twice", AnimationLoop, 2, []time.Duration{40 * ms}, []int{0}, 2, true},
+		{"single frame loop", AnimationLoop, 1, []time.Duration{10 * ms, 10 * ms}, []int{0, 0}, 2, true},
+		{"ping pong", AnimationPingPong, 3, []time.Duration{10 * ms, 10 * ms, 10 * ms, 10 * ms, 10 * ms},
+			[]int{1, 2, 1, 0, 1}, 1, true},
+		{"single frame ping pong", AnimationPingPong, 1, []time.Duration{10 * ms}, []int{0}, 1, true},
+		{"once", AnimationOnce, 3, []time.Duration{10 * ms, 10 * ms}, []int{1, 2}, 0, true},
+		{"once finishes", AnimationOnce, 3, []time.Duration{20 * ms, 10 * ms, 10 * ms}, []int{2, 2, 2}, 1, false},
+		{"once finishes early", AnimationOnce, 2, []time.Duration{100 * ms}, []int{1}, 1, false},
+	}
+
+	for _, test := range tests {
+		completes := 0
+
+		anim := CreateAnimation(test.mode, StripFrames(0, 0, 16, 16, test.frames, 10*ms))
+		anim.OnComplete = func() { completes++ }
+		anim.Reset()
+		anim.playing = true
+
+		var frames []int
+
+		for _, delta := range test.deltas {
+			anim.advance(delta)
+			frames = append(frames, anim.Frame())
+		}
+
+		if !reflect.DeepEqual(frames, test.wantFrames) {
+			t.Errorf("%s: got frames %v, want %v", test.name, frames, test.wantFrames)
+		}
+
+		if completes != test.wantCompletes {
+			t.Errorf("%s: got %d completes, want %d", test.name, completes, test.wantCompletes)
+		}
+
+		if anim.Playing() != test.wantPlaying {
+			t.Errorf("%s: got playing %v, want %v", test.name, anim.Playing(), test.wantPlaying)
+		}
+	}
+}
+
+func TestStripFrames(t *testing.T) {
+	got := StripFrames(8, 4, 16, 32, 3, time.Second)
+	want := []Frame{
+		{TexRect{8, 4, 16, 32}, time.Second},
+		{TexRect{24, 4, 16, 32}, time.Second},
+		{TexRect{40, 4, 16, 32}, time.Second},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,190 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+)
+
+/*
+Sprite batching, consecutive render objects in the draw order which share a texture and the default shader are merged
+into a single draw call. The visible shapes of every render object in a batch are transformed on the cpu (grouped rotation,
+rotation, translation and camera) into a batch render object for the texture, which is drawn without any transformation.
+The blend state is global, set once in Init and never changed per render object, so it is not part of the batch key.
+Per render object blend modes would have to be compared in batchableWith.
+
+Layer order is kept as only neighbouring render objects are batched, instanced, clipped and invisible render objects are
+never batched.
+Batching trades a draw call per render object for transforming their vertices each render so it is disabled by default.
+*/
+
+const batchInitialQuads = 1024
+
+// BatchStats ... totals for a single render
+type BatchStats struct {
+	// Batches ... draw calls made for batches
+	Batches int
+	// BatchedObjects ... render objects drawn in batches
+	BatchedObjects int
+	// DrawCallsSaved ... draw calls which would have been made without batching
+	DrawCallsSaved int
+}
+
+var (
+	batching                   bool
+	batchStats, lastBatchStats BatchStats
+
+	// batchObjs ... render object drawing the batches of each texture
+	batchObjs = make(map[*opengl.Texture]*RenderObject)
+
+	// Vertex data reused each render
+	batchVerts, batchTexs, batchColours []float32
+)
+
+// SetBatching ... batch render objects sharing a texture into a single draw call
+func SetBatching(enabled bool) {
+	batching = enabled
+}
+
+func Batching() bool {
+	return batching
+}
+
+// LastBatchStats ... batching totals of the last render, must be called on the main thread
+func LastBatchStats() BatchStats {
+	return lastBatchStats
+}
+
+// drawRenderObjects ... draw render objects in order, batching neighbouring render objects when enabled
+func drawRenderObjects(objs []*RenderObject) {
+	if !batching {
+		for _, obj := range objs {
+			obj.Render()
+		}
+
+		return
+	}
+
+	for start := 0; start < len(objs); {
+		first := objs[start]
+		end := start + 1
+
+		if first.batchable() {
+			// Invisible render objects draw nothing so don't end the batch
+			for end < len(objs) && (objs[end].invisible || objs[end].batchableWith(first)) {
+				end++
+			}
+		}
+
+		if end-start == 1 {
+			first.Render()
+		} else {
+			renderBatch(objs[start:end], first.texture)
+		}
+
+		start = end
+	}
+}
+
+func (obj *RenderObject) batchable() bool {
+	return !obj.invisible && !obj.clipped() && obj.instanced == nil && obj.vao.UsesDefaultShader()
+}
+
+// batchableWith ... the batch key is the texture, the shader must be the default and blending is global
+func (obj *RenderObject) batchableWith(other *RenderObject) bool {
+	return obj.batchable() && obj.texture == other.texture
+}
+
+// renderBatch ... draw the visible shapes of objs in a single draw call
+func renderBatch(objs []*RenderObject, texture *opengl.Texture) {
+	batchVerts, batchTexs, batchColours = batchVerts[:0], batchTexs[:0], batchColours[:0]
+	drawn := 0
+
+	for _, obj := range objs {
+		if obj.invisible {
+			continue
+		}
+
+		vertNum := len(batchVerts) / opengl.DEFAULT_VECTOR_SIZE
+		obj.appendBatch()
+
+		if len(batchVerts)/opengl.DEFAULT_VECTOR_SIZE > vertNum {
+			drawn++
+		}
+	}
+
+	if drawn == 0 {
+		return
+	}
+
+	vertNum := len(batchVerts) / opengl.DEFAULT_VECTOR_SIZE
+	batch := batchObjs[texture]
+
+	if batch == nil || batch.maxVert < vertNum {
+		batch = growRenderObject(batch, vertNum/6, batchInitialQuads, texture.File())
+		batch.screenSpace = true
+		batchObjs[texture] = batch
+	}
+
+	vao := batch.vao
+	vao.UpdateVertBufferIndex(0, batchVerts)
+	vao.UpdateTexBufferIndex(0, batchTexs)
+	vao.UpdateColourBufferIndex(0, batchColours)
+	batch.freeVert = vertNum
+
+	batch.Render()
+
+	batchStats.Batches++
+	batchStats.BatchedObjects += drawn
+	batchStats.DrawCallsSaved += drawn - 1
+}
+
+// appendBatch ... append the visible shapes of the render object transformed into view pixels
+func (obj *RenderObject) appendBatch() {
+	var firsts, counts []int32
+
+	if obj.cull.disabled {
+		firsts, counts = []int32{0}, []int32{int32(obj.freeVert)}
+	} else {
+		firsts, counts = obj.visibleRanges()
+	}
+
+	for i, first := range firsts {
+		start, end := int(first), int(first+counts[i])
+
+		for vert := start; vert < end; vert++ {
+			x, y := obj.transformedVert(vert)
+
+			if !obj.screenSpace {
+				x, y = drawView.worldToView(x, y)
+			}
+
+			batchVerts = append(batchVerts, x, y)
+		}
+
+		batchTexs = append(batchTexs, obj.vao.TexCoords(start, end)...)
+		batchColours = append(batchColours, obj.vao.Colours(start, end)...)
+	}
+}
+
+func deleteBatchObjects() {
+	for texture, obj := range batchObjs {
+		obj.Delete()
+		delete(batchObjs, texture)
+	}
+}
+
+/*
+Batching jobs
+*/
+
+func callSetBatching(job RenderObjectJob) {
+	SetBatching(job.params[0].(bool))
+}
+
+func SetBatchingJob(enabled bool) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{enabled},
+		nil,
+		callSetBatching,
+	}
+}
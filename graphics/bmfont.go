@@ -0,0 +1,306 @@
+package graphics
+
+import (
+	"Gopengl/util"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"gopengl/graphics/opengl"
+	"io/ioutil"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+/*
+AngelCode BMFont support, both the text and XML descriptor formats are supported.
+Pages are loaded with LoadTexture relative to the descriptor file.
+*/
+
+type BMFont struct {
+	file       string
+	size       float32
+	lineHeight float32
+	base       float32
+	pages      []*opengl.Texture
+	glyphs     map[rune]glyph
+	kernings   map[[2]rune]float32
+}
+
+// bmDescriptor ... the parts of a BMFont descriptor that are used, matches the XML format
+type bmDescriptor struct {
+	Info struct {
+		Size int `xml:"size,attr"`
+	} `xml:"info"`
+	Common struct {
+		LineHeight int `xml:"lineHeight,attr"`
+		Base       int `xml:"base,attr"`
+	} `xml:"common"`
+	Pages    []bmPage    `xml:"pages>page"`
+	Chars    []bmChar    `xml:"chars>char"`
+	Kernings []bmKerning `xml:"kernings>kerning"`
+}
+
+type bmPage struct {
+	ID   int    `xml:"id,attr"`
+	File string `xml:"file,attr"`
+}
+
+type bmChar struct {
+	ID       int `xml:"id,attr"`
+	X        int `xml:"x,attr"`
+	Y        int `xml:"y,attr"`
+	Width    int `xml:"width,attr"`
+	Height   int `xml:"height,attr"`
+	XOffset  int `xml:"xoffset,attr"`
+	YOffset  int `xml:"yoffset,attr"`
+	XAdvance int `xml:"xadvance,attr"`
+	Page     int `xml:"page,attr"`
+}
+
+type bmKerning struct {
+	First  int `xml:"first,attr"`
+	Second int `xml:"second,attr"`
+	Amount int `xml:"amount,attr"`
+}
+
+// LoadBMFont ... load a .fnt descriptor and its pages
+func LoadBMFont(file string) *BMFont {
+	f := &BMFont{}
+	f.load(file)
+
+	return f
+}
+
+func (f *BMFont) load(file string) {
+	data, err := ioutil.ReadFile(util.RelativePath(file))
+	if err != nil {
+		panic(fmt.Errorf("font %q not found on disk: %v", file, err))
+	}
+
+	var desc bmDescriptor
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		err = xml.Unmarshal(data, &desc)
+	} else {
+		err = parseBMText(string(data), &desc)
+	}
+
+	if err != nil {
+		panic(fmt.Errorf("BMFont parse error in %q, error: %v", file, err))
+	}
+
+	// Negative sizes are written by BMFont when matching the character height, text is scaled by the size so it cannot be 0
+	size := float32(math.Abs(float64(desc.Info.Size)))
+	if size <= 0 {
+		panic(fmt.Errorf("BMFont %q has no size", file))
+	}
+
+	f.file = file
+	f.size = size
+	f.lineHeight = float32(desc.Common.LineHeight)
+	f.base = float32(desc.Common.Base)
+	f.pages = make([]*opengl.Texture, len(desc.Pages))
+	f.glyphs = make(map[rune]glyph, len(desc.Chars))
+	f.kernings = make(map[[2]rune]float32, len(desc.Kernings))
+
+	for _, page := range desc.Pages {
+		if page.ID < 0 || page.ID >= len(f.pages) {
+			panic(fmt.Errorf("BMFont %q has invalid page id %d", file, page.ID))
+		}
+
+		f.pages[page.ID] = opengl.LoadTexture(path.Join(path.Dir(file), page.File))
+	}
+
+	for _, char := range desc.Chars {
+		f.glyphs[rune(char.ID)] = glyph{
+			TexRect{float32(char.X), float32(char.Y), float32(char.Width), float32(char.Height)},
+			float32(char.XOffset),
+			// Offsets are given from the top of the line, glyphs are offset from the baseline
+			float32(char.YOffset) - f.base,
+			float32(char.XAdvance),
+		}
+	}
+
+	for _, kerning := range desc.Kernings {
+		f.kernings[[2]rune{rune(kerning.First), rune(kerning.Second)}] = float32(kerning.Amount)
+	}
+}
+
+/*
+Texture returns the first page, text render objects can only use a single texture so multiple pages are not
+supported when drawing text.
+*/
+func (f *BMFont) Texture() *opengl.Texture {
+	if len(f.pages) != 1 {
+		panic(fmt.Errorf("BMFont %q has %d pages, only single page fonts can be drawn", f.file, len(f.pages)))
+	}
+
+	return f.pages[0]
+}
+
+func (f *BMFont) Pages() []*opengl.Texture {
+	return f.pages
+}
+
+func (f *BMFont) Size() float32 {
+	return f.size
+}
+
+func (f *BMFont) LineHeight() float32 {
+	return f.lineHeight
+}
+
+func (f *BMFont) Ascent() float32 {
+	return f.base
+}
+
+func (f *BMFont) glyph(r rune) (glyph, bool) {
+	g, exists := f.glyphs[r]
+
+	return g, exists
+}
+
+func (f *BMFont) kern(r0, r1 rune) float32 {
+	return f.kernings[[2]rune{r0, r1}]
+}
+
+/*
+Text descriptor parsing, each line is a tag followed by key=value pairs, values may be quoted.
+*/
+
+func parseBMText(data string, desc *bmDescriptor) error {
+	for n, line := range strings.Split(data, "\n") {
+		tag, attrs := parseBMLine(line)
+		ints := bmInts{attrs: attrs}
+
+		switch tag {
+		case "info":
+			desc.Info.Size = ints.get("size")
+		case "common":
+			desc.Common.LineHeight = ints.get("lineHeight")
+			desc.Common.Base = ints.get("base")
+		case "page":
+			desc.Pages = append(desc.Pages, bmPage{
+				ints.get("id"),
+				attrs["file"],
+			})
+		case "char":
+			desc.Chars = append(desc.Chars, bmChar{
+				ints.get("id"),
+				ints.get("x"),
+				ints.get("y"),
+				ints.get("width"),
+				ints.get("height"),
+				ints.get("xoffset"),
+				ints.get("yoffset"),
+				ints.get("xadvance"),
+				ints.get("page"),
+			})
+		case "kerning":
+			desc.Kernings = append(desc.Kernings, bmKerning{
+				ints.get("first"),
+				ints.get("second"),
+				ints.get("amount"),
+			})
+		}
+
+		if ints.err != nil {
+			return fmt.Errorf("line %d: %v", n+1, ints.err)
+		}
+	}
+
+	return nil
+}
+
+// parseBMLine ... split a line into its tag and attributes
+func parseBMLine(line string) (string, map[string]string) {
+	line = strings.TrimSpace(line)
+	attrs := make(map[string]string)
+
+	end := strings.IndexAny(line, " \t")
+	if end == -1 {
+		return line, attrs
+	}
+
+	tag := line[:end]
+	rest := line[end:]
+
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		eq := strings.IndexByte(rest, '=')
+
+		if eq == -1 {
+			break
+		}
+
+		key := rest[:eq]
+		rest = rest[eq+1:]
+
+		var value string
+
+		if strings.HasPrefix(rest, "\"") {
+			closing := strings.IndexByte(rest[1:], '"')
+			if closing == -1 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:closing+1], rest[closing+2:]
+			}
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end == -1 {
+				end = len(rest)
+			}
+
+			value, rest = rest[:end], rest[end:]
+		}
+
+		attrs[key] = value
+	}
+
+	return tag, attrs
+}
+
+// bmInts ... parses integer attributes, keeping the first error. Missing attributes are 0
+type bmInts struct {
+	attrs map[string]string
+	err   error
+}
+
+func (ints *bmInts) get(key string) int {
+	value, exists := ints.attrs[key]
+	if !exists || ints.err != nil {
+		return 0
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		ints.err = fmt.Errorf("invalid %s: %v", key, err)
+	}
+
+	return i
+}
+
+/*
+BMFont jobs
+*/
+
+func callLoadBMFont(job RenderObjectJob) {
+	(*BMFont)(job.retVal).load(job.params[0].(string))
+}
+
+// LoadBMFontJob ... the returned font is loaded once the job has been performed, it can be passed to other jobs immediately
+func LoadBMFontJob(file string) *BMFont {
+	f := &BMFont{}
+
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{file},
+		unsafe.Pointer(f),
+		callLoadBMFont,
+	}
+
+	return f
+}
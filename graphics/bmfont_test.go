@@ -0,0 +1,98 @@
+package graphics
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testBMText = `info face="Test Sans" size=-32 bold=0 italic=0
+common lineHeight=36 base=28 scaleW=256 scaleH=256 pages=1
+page id=0 file="test sans.png"
+chars count=2
+char id=65   x=2 y=4  width=20 height=24 xoffset=1 yoffset=4 xadvance=22 page=0
+char id=86	x=24 y=4 width=18 height=24 xoffset=0 yoffset=4 xadvance=19 page=0
+kernings count=1
+kerning first=65 second=86 amount=-2
+`
+
+func TestParseBMText(t *testing.T) {
+	var want bmDescriptor
+	want.Info.Size = -32
+	want.Common.LineHeight = 36
+	want.Common.Base = 28
+	want.Pages = []bmPage{{0, "test sans.png"}}
+	want.Chars = []bmChar{
+		{65, 2, 4, 20, 24, 1, 4, 22, 0},
+		{86, 24, 4, 18, 24, 0, 4, 19, 0},
+	}
+	want.Kernings = []bmKerning{{65, 86, -2}}
+
+	var got bmDescriptor
+
+	if err := parseBMText(testBMText, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseBMTextErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"unknown tag", "unknown a=1\n", false},
+		{"invalid int", "char id=65 x=two\n", true},
+		{"invalid size", "info size=big\n", true},
+	}
+
+	for _, test := range tests {
+		var desc bmDescriptor
+
+		if err := parseBMText(test.data, &desc); (err != nil) != test.wantErr {
+			t.Errorf("%s: error %v, want error %v", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestParseBMLine(t *testing.T) {
+	tests := []struct {
+		line      string
+		wantTag   string
+		wantAttrs map[string]string
+	}{
+		{"common", "common", map[string]string{}},
+		{"  page id=0 file=\"a b.png\"  ", "page", map[string]string{"id": "0", "file": "a b.png"}},
+		{"info face=\"unterminated", "info", map[string]string{"face": "unterminated"}},
+		{"char\tid=1\tx=2", "char", map[string]string{"id": "1", "x": "2"}},
+	}
+
+	for _, test := range tests {
+		tag, attrs := parseBMLine(test.line)
+
+		if tag != test.wantTag || !reflect.DeepEqual(attrs, test.wantAttrs) {
+			t.Errorf("%q: got %q %v, want %q %v", test.line, tag, attrs, test.wantTag, test.wantAttrs)
+		}
+	}
+}
+
+func TestLoadBMFontWithoutSize(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("root_file_path", dir)
+
+	for _, data := range []string{"common lineHeight=36 base=28\n", "info size=0\n", `<font><info size="0"/></font>`} {
+		if err := ioutil.WriteFile(filepath.Join(dir, "font.fnt"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if !panics(func() { LoadBMFont("font.fnt") }) {
+			t.Errorf("%q: did not panic", data)
+		}
+	}
+}
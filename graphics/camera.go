@@ -0,0 +1,224 @@
+package graphics
+
+import (
+	"math"
+)
+
+/*
+The camera is shared by every world space render object, it is applied in the vertex shader so moving it
+does not modify any vertices. Screen space render objects (eg HUDs) ignore the camera.
+
+The camera position is the world position drawn at the top left of the screen when not zoomed or rotated,
+zoom and rotation are performed about the centre of the screen.
+*/
+
+type Camera struct {
+	x, y float32
+	zoom float32
+	rot  float32
+}
+
+// view ... a camera drawn to a view of width x height pixels, zoom and rotation are about its centre
+type view struct {
+	camera        *Camera
+	width, height float32
+}
+
+var camera = CreateCamera()
+
+func CreateCamera() *Camera {
+	return &Camera{zoom: 1}
+}
+
+// CurrentCamera ... the camera used by world space render objects
+func CurrentCamera() *Camera {
+	return camera
+}
+
+// SetCamera ... replace the camera used by world space render objects
+func SetCamera(c *Camera) {
+	camera = c
+}
+
+func (c *Camera) SetPosition(x, y float32) {
+	c.x = x
+	c.y = y
+}
+
+// Move ... move the camera by x, y world pixels
+func (c *Camera) Move(x, y float32) {
+	c.x += x
+	c.y += y
+}
+
+// SetZoom ... zoom greater than 1 magnifies the world
+func (c *Camera) SetZoom(zoom float32) {
+	if zoom <= 0 {
+		panic("Camera zoom must be positive")
+	}
+
+	c.zoom = zoom
+}
+
+// SetRotation ... rotate the view clockwise by rad radians about the centre of the screen
+func (c *Camera) SetRotation(rad float32) {
+	c.rot = rad
+}
+
+func (c *Camera) Position() (x, y float32) {
+	return c.x, c.y
+}
+
+func (c *Camera) Zoom() float32 {
+	return c.zoom
+}
+
+func (c *Camera) Rotation() float32 {
+	return c.rot
+}
+
+// WorldToScreen ... convert a world position to a position on the screen in pixels, use Viewport.WorldToScreen for viewports
+func (c *Camera) WorldToScreen(x, y float32) (screenX, screenY float32) {
+	return windowView(c).worldToView(x, y)
+}
+
+// ScreenToWorld ... convert a position on the screen in pixels, eg the mouse position, to a world position
+func (c *Camera) ScreenToWorld(x, y float32) (worldX, worldY float32) {
+	return windowView(c).viewToWorld(x, y)
+}
+
+func (c *Camera) cosSin() (cos, sin float32) {
+	return float32(math.Cos(float64(c.rot))), float32(math.Sin(float64(c.rot)))
+}
+
+// windowView ... the camera drawn to the whole window
+func windowView(c *Camera) view {
+	return view{c, windowWidth, windowHeight}
+}
+
+// worldToView ... convert a world position to a position in pixels from the top left of the view
+func (v view) worldToView(x, y float32) (viewX, viewY float32) {
+	c := v.camera
+	centreX, centreY := v.centre()
+	cos, sin := c.cosSin()
+
+	x -= c.x + centreX
+	y -= c.y + centreY
+
+	viewX = (cos*x+sin*y)*c.zoom + centreX
+	viewY = (cos*y-sin*x)*c.zoom + centreY
+
+	return viewX, viewY
+}
+
+// viewToWorld ... convert a position in pixels from the top left of the view to a world position
+func (v view) viewToWorld(x, y float32) (worldX, worldY float32) {
+	c := v.camera
+	centreX, centreY := v.centre()
+	cos, sin := c.cosSin()
+
+	x = (x - centreX) / c.zoom
+	y = (y - centreY) / c.zoom
+
+	worldX = cos*x - sin*y + c.x + centreX
+	worldY = sin*x + cos*y + c.y + centreY
+
+	return worldX, worldY
+}
+
+func (v view) centre() (x, y float32) {
+	return v.width / 2, v.height / 2
+}
+
+// WorldToScreen ... convert using the current camera
+func WorldToScreen(x, y float32) (screenX, screenY float32) {
+	return camera.WorldToScreen(x, y)
+}
+
+// ScreenToWorld ... convert using the current camera
+func ScreenToWorld(x, y float32) (worldX, worldY float32) {
+	return camera.ScreenToWorld(x, y)
+}
+
+/*
+Camera jobs, the camera is passed as the first parameter.
+*/
+
+func callCameraSetPosition(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Camera).SetPosition(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+func callCameraMove(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Camera).Move(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+func callCameraSetZoom(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Camera).SetZoom(params[1].(float32))
+}
+
+func callCameraSetRotation(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Camera).SetRotation(params[1].(float32))
+}
+
+func callSetCamera(job RenderObjectJob) {
+	SetCamera(job.params[0].(*Camera))
+}
+
+func (c *Camera) SetPositionJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{c, x, y},
+		nil,
+		callCameraSetPosition,
+	}
+}
+
+func (c *Camera) MoveJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{c, x, y},
+		nil,
+		callCameraMove,
+	}
+}
+
+func (c *Camera) SetZoomJob(zoom float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{c, zoom},
+		nil,
+		callCameraSetZoom,
+	}
+}
+
+func (c *Camera) SetRotationJob(rad float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{c, rad},
+		nil,
+		callCameraSetRotation,
+	}
+}
+
+func SetCameraJob(c *Camera) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{c},
+		nil,
+		callSetCamera,
+	}
+}
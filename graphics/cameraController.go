@@ -0,0 +1,480 @@
+package graphics
+
+import (
+	"math"
+	"time"
+	"unsafe"
+)
+
+/*
+Camera controllers move a camera each render, game go routines only set targets (follow target, zoom, trauma)
+and the controller eases the camera towards them on the main thread.
+
+While a controller is attached it owns the cameras position, zoom and rotation, use the controllers methods
+instead of setting the camera directly. Controllers of a camera drawn by viewports zoom and follow about the centre
+of the first viewport drawing it.
+*/
+
+type CameraController struct {
+	camera *Camera
+
+	// Position of the camera before shake is applied
+	x, y, rot float32
+
+	// Follow, the target is kept within the dead zone about the centre of the screen
+	targetX, targetY *float32
+	// DeadZoneWidth, DeadZoneHeight ... size of the dead zone in world pixels
+	DeadZoneWidth, DeadZoneHeight float32
+	// FollowSmoothing ... fraction of the distance to the target remaining after a second, 0 snaps to the target
+	FollowSmoothing float32
+
+	// Bounds the visible world is kept within
+	bounded                bool
+	minX, minY, maxX, maxY float32
+
+	// Zoom towards an anchor point on the screen
+	targetZoom       float32
+	anchorX, anchorY float32
+	// ZoomSmoothing ... fraction of the zoom remaining after a second, 0 snaps to the target
+	ZoomSmoothing float32
+
+	// Shake, the amount of shake is trauma squared
+	trauma float32
+	// ShakeDecay ... trauma removed per second
+	ShakeDecay float32
+	// MaxShakeOffset, MaxShakeAngle ... offset in pixels and angle in radians at full trauma
+	MaxShakeOffset, MaxShakeAngle float32
+	shakeTime                     float32
+}
+
+var cameraControllers []*CameraController
+var lastCameraUpdate time.Time
+
+// CreateCameraController ... attach a controller to the camera, the controller is updated at the start of each render
+func CreateCameraController(c *Camera) *CameraController {
+	cc := &CameraController{}
+	cc.init(c)
+
+	return cc
+}
+
+func (cc *CameraController) init(c *Camera) {
+	cc.camera = c
+	cc.x, cc.y, cc.rot = c.x, c.y, c.rot
+	cc.targetZoom = c.zoom
+	cc.anchorX, cc.anchorY = cameraView(c).centre()
+	cc.FollowSmoothing = 0.01
+	cc.ZoomSmoothing = 0.01
+	cc.ShakeDecay = 1
+	cc.MaxShakeOffset = 16
+	cc.MaxShakeAngle = 0.05
+
+	cameraControllers = append(cameraControllers, cc)
+}
+
+// Detach ... stop updating the camera, any shake is removed
+func (cc *CameraController) Detach() {
+	for i, controller := range cameraControllers {
+		if controller == cc {
+			cameraControllers = append(cameraControllers[:i], cameraControllers[i+1:]...)
+			break
+		}
+	}
+
+	cc.camera.x, cc.camera.y, cc.camera.rot = cc.x, cc.y, cc.rot
+}
+
+func (cc *CameraController) Camera() *Camera {
+	return cc.camera
+}
+
+// SetPosition ... move the camera immediately, the follow target will still be followed
+func (cc *CameraController) SetPosition(x, y float32) {
+	cc.x = x
+	cc.y = y
+}
+
+func (cc *CameraController) SetRotation(rad float32) {
+	cc.rot = rad
+}
+
+// Follow ... follow the world position pointed to by x, y, the values are checked each render
+func (cc *CameraController) Follow(x, y *float32) {
+	cc.targetX = x
+	cc.targetY = y
+}
+
+func (cc *CameraController) StopFollowing() {
+	cc.targetX = nil
+	cc.targetY = nil
+}
+
+// SetBounds ... keep the visible world within the rectangle, if it is smaller than the view it is centred
+func (cc *CameraController) SetBounds(minX, minY, maxX, maxY float32) {
+	cc.bounded = true
+	cc.minX, cc.minY, cc.maxX, cc.maxY = minX, minY, maxX, maxY
+}
+
+func (cc *CameraController) ClearBounds() {
+	cc.bounded = false
+}
+
+// ZoomTowards ... ease to zoom keeping the world position under screenX, screenY (eg the mouse) fixed
+func (cc *CameraController) ZoomTowards(zoom, screenX, screenY float32) {
+	if zoom <= 0 {
+		panic("Camera zoom must be positive")
+	}
+
+	cc.targetZoom = zoom
+	cc.anchorX = screenX
+	cc.anchorY = screenY
+}
+
+// SetZoom ... ease to zoom about the centre of the screen
+func (cc *CameraController) SetZoom(zoom float32) {
+	centreX, centreY := cameraView(cc.camera).centre()
+	cc.ZoomTowards(zoom, centreX, centreY)
+}
+
+// AddTrauma ... add to the amount of shake, trauma is between 0 and 1
+func (cc *CameraController) AddTrauma(trauma float32) {
+	cc.trauma = clamp(cc.trauma+trauma, 0, 1)
+}
+
+func (cc *CameraController) Trauma() float32 {
+	return cc.trauma
+}
+
+/*
+Controller updating, performed at the start of each render
+*/
+
+func updateCameraControllers() {
+	now := time.Now()
+
+	if lastCameraUpdate.IsZero() {
+		lastCameraUpdate = now
+	}
+
+	delta := float32(now.Sub(lastCameraUpdate).Seconds())
+	lastCameraUpdate = now
+
+	for _, cc := range cameraControllers {
+		cc.Update(delta)
+	}
+}
+
+// Update ... advance the controller by delta seconds
+func (cc *CameraController) Update(delta float32) {
+	c := cc.camera
+
+	// Work from the unshaken position
+	c.x, c.y, c.rot = cc.x, cc.y, cc.rot
+
+	cc.updateZoom(delta)
+	cc.updateFollow(delta)
+
+	if cc.bounded {
+		cc.clampToBounds()
+	}
+
+	cc.x, cc.y = c.x, c.y
+
+	cc.updateShake(delta)
+}
+
+func (cc *CameraController) updateZoom(delta float32) {
+	c := cc.camera
+
+	if c.zoom == cc.targetZoom {
+		return
+	}
+
+	v := cameraView(c)
+	beforeX, beforeY := v.viewToWorld(cc.anchorX, cc.anchorY)
+
+	c.zoom += (cc.targetZoom - c.zoom) * smoothing(cc.ZoomSmoothing, delta)
+	if float32(math.Abs(float64(cc.targetZoom-c.zoom))) < 0.0001 {
+		c.zoom = cc.targetZoom
+	}
+
+	afterX, afterY := v.viewToWorld(cc.anchorX, cc.anchorY)
+
+	c.x += beforeX - afterX
+	c.y += beforeY - afterY
+}
+
+func (cc *CameraController) updateFollow(delta float32) {
+	if cc.targetX == nil || cc.targetY == nil {
+		return
+	}
+
+	c := cc.camera
+	viewX, viewY := cameraView(c).centre()
+	centreX, centreY := c.x+viewX, c.y+viewY
+
+	moveX := deadZoneDistance(*cc.targetX-centreX, cc.DeadZoneWidth/2)
+	moveY := deadZoneDistance(*cc.targetY-centreY, cc.DeadZoneHeight/2)
+
+	factor := smoothing(cc.FollowSmoothing, delta)
+	c.x += moveX * factor
+	c.y += moveY * factor
+}
+
+func (cc *CameraController) clampToBounds() {
+	c := cc.camera
+	viewX, viewY := cameraView(c).centre()
+	halfWidth := viewX / c.zoom
+	halfHeight := viewY / c.zoom
+
+	centreX := clampCentre(c.x+viewX, cc.minX+halfWidth, cc.maxX-halfWidth)
+	centreY := clampCentre(c.y+viewY, cc.minY+halfHeight, cc.maxY-halfHeight)
+
+	c.x = centreX - viewX
+	c.y = centreY - viewY
+}
+
+func (cc *CameraController) updateShake(delta float32) {
+	c := cc.camera
+
+	cc.trauma = clamp(cc.trauma-cc.ShakeDecay*delta, 0, 1)
+	if cc.trauma == 0 {
+		return
+	}
+
+	cc.shakeTime += delta
+	shake := cc.trauma * cc.trauma
+
+	c.x += cc.MaxShakeOffset * shake * shakeNoise(cc.shakeTime, 0)
+	c.y += cc.MaxShakeOffset * shake * shakeNoise(cc.shakeTime, 1)
+	c.rot += cc.MaxShakeAngle * shake * shakeNoise(cc.shakeTime, 2)
+}
+
+// smoothing ... fraction of the remaining distance to move in delta seconds
+func smoothing(remaining, delta float32) float32 {
+	return 1 - float32(math.Pow(float64(remaining), float64(delta)))
+}
+
+// deadZoneDistance ... distance to move so offset is within plus or minus halfSize
+func deadZoneDistance(offset, halfSize float32) float32 {
+	if offset > halfSize {
+		return offset - halfSize
+	}
+
+	if offset < -halfSize {
+		return offset + halfSize
+	}
+
+	return 0
+}
+
+// clampCentre ... clamp the centre between min and max, centred between them if they overlap
+func clampCentre(centre, min, max float32) float32 {
+	if min > max {
+		return (min + max) / 2
+	}
+
+	return clamp(centre, min, max)
+}
+
+func clamp(value, min, max float32) float32 {
+	if value < min {
+		return min
+	}
+
+	if value > max {
+		return max
+	}
+
+	return value
+}
+
+// shakeNoise ... smooth noise between -1 and 1, each seed gives a different curve
+func shakeNoise(t float32, seed int) float32 {
+	offset := float64(seed) * 17.3
+	t64 := float64(t)
+
+	return float32(math.Sin(t64*23+offset)*0.5 + math.Sin(t64*37+offset*2.1)*0.3 + math.Sin(t64*53+offset*3.7)*0.2)
+}
+
+/*
+Camera controller jobs, the controller is passed as the first parameter.
+*/
+
+func callCreateCameraController(job RenderObjectJob) {
+	(*CameraController)(job.retVal).init(job.params[0].(*Camera))
+}
+
+func callCameraControllerFollow(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*CameraController).Follow(
+		params[1].(*float32),
+		params[2].(*float32),
+	)
+}
+
+func callCameraControllerSetBounds(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*CameraController).SetBounds(
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+		params[4].(float32),
+	)
+}
+
+func callCameraControllerZoomTowards(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*CameraController).ZoomTowards(
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+	)
+}
+
+func callCameraControllerAddTrauma(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*CameraController).AddTrauma(params[1].(float32))
+}
+
+func callCameraControllerSetPosition(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*CameraController).SetPosition(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+func callCameraControllerSetRotation(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*CameraController).SetRotation(params[1].(float32))
+}
+
+func callCameraControllerSetZoom(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*CameraController).SetZoom(params[1].(float32))
+}
+
+func callCameraControllerStopFollowing(job RenderObjectJob) {
+	job.params[0].(*CameraController).StopFollowing()
+}
+
+func callCameraControllerClearBounds(job RenderObjectJob) {
+	job.params[0].(*CameraController).ClearBounds()
+}
+
+func callCameraControllerDetach(job RenderObjectJob) {
+	job.params[0].(*CameraController).Detach()
+}
+
+// CreateCameraControllerJob ... the returned controller is populated once the job has been performed, its Job methods can be used immediately
+func CreateCameraControllerJob(c *Camera) *CameraController {
+	cc := &CameraController{}
+
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{c},
+		unsafe.Pointer(cc),
+		callCreateCameraController,
+	}
+
+	return cc
+}
+
+// FollowJob ... x and y are read each render in the same way as SetTranslate
+func (cc *CameraController) FollowJob(x, y *float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc, x, y},
+		nil,
+		callCameraControllerFollow,
+	}
+}
+
+func (cc *CameraController) SetBoundsJob(minX, minY, maxX, maxY float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc, minX, minY, maxX, maxY},
+		nil,
+		callCameraControllerSetBounds,
+	}
+}
+
+func (cc *CameraController) ZoomTowardsJob(zoom, screenX, screenY float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc, zoom, screenX, screenY},
+		nil,
+		callCameraControllerZoomTowards,
+	}
+}
+
+func (cc *CameraController) AddTraumaJob(trauma float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc, trauma},
+		nil,
+		callCameraControllerAddTrauma,
+	}
+}
+
+func (cc *CameraController) SetPositionJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc, x, y},
+		nil,
+		callCameraControllerSetPosition,
+	}
+}
+
+func (cc *CameraController) SetRotationJob(rad float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc, rad},
+		nil,
+		callCameraControllerSetRotation,
+	}
+}
+
+func (cc *CameraController) SetZoomJob(zoom float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc, zoom},
+		nil,
+		callCameraControllerSetZoom,
+	}
+}
+
+func (cc *CameraController) StopFollowingJob() {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc},
+		nil,
+		callCameraControllerStopFollowing,
+	}
+}
+
+func (cc *CameraController) ClearBoundsJob() {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc},
+		nil,
+		callCameraControllerClearBounds,
+	}
+}
+
+func (cc *CameraController) DetachJob() {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{cc},
+		nil,
+		callCameraControllerDetach,
+	}
+}
@@ -0,0 +1,121 @@
+package graphics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSmoothing(t *testing.T) {
+	tests := []struct {
+		remaining, delta float32
+		want             float32
+	}{
+		{0, 0.016, 1},
+		{1, 0.016, 0},
+		{0.5, 0, 0},
+		{0.5, 1, 0.5},
+		{0.25, 0.5, 0.5},
+		{0.5, 2, 0.75},
+	}
+
+	for _, test := range tests {
+		if got := smoothing(test.remaining, test.delta); math.Abs(float64(got-test.want)) > 1e-6 {
+			t.Errorf("smoothing(%v, %v): got %v, want %v", test.remaining, test.delta, got, test.want)
+		}
+	}
+}
+
+func TestDeadZoneDistance(t *testing.T) {
+	tests := []struct {
+		offset, halfSize float32
+		want             float32
+	}{
+		{0, 10, 0},
+		{10, 10, 0},
+		{-10, 10, 0},
+		{15, 10, 5},
+		{-15, 10, -5},
+		{3, 0, 3},
+		{-3, 0, -3},
+	}
+
+	for _, test := range tests {
+		if got := deadZoneDistance(test.offset, test.halfSize); got != test.want {
+			t.Errorf("deadZoneDistance(%v, %v): got %v, want %v", test.offset, test.halfSize, got, test.want)
+		}
+	}
+}
+
+func TestClampCentre(t *testing.T) {
+	tests := []struct {
+		name             string
+		centre, min, max float32
+		want             float32
+	}{
+		{"inside", 50, 0, 100, 50},
+		{"below", -20, 0, 100, 0},
+		{"above", 120, 0, 100, 100},
+		{"on min", 0, 0, 100, 0},
+		{"equal bounds", 30, 40, 40, 40},
+		{"view larger than bounds", 0, 60, 40, 50},
+	}
+
+	for _, test := range tests {
+		if got := clampCentre(test.centre, test.min, test.max); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		value, min, max float32
+		want            float32
+	}{
+		{0.5, 0, 1, 0.5},
+		{-0.5, 0, 1, 0},
+		{1.5, 0, 1, 1},
+		{1, 0, 1, 1},
+	}
+
+	for _, test := range tests {
+		if got := clamp(test.value, test.min, test.max); got != test.want {
+			t.Errorf("clamp(%v, %v, %v): got %v, want %v", test.value, test.min, test.max, got, test.want)
+		}
+	}
+}
+
+func TestClampToBounds(t *testing.T) {
+	// Bounds of 2000 by 1000 pixels viewed through the default 800 by 600 window
+	tests := []struct {
+		name         string
+		x, y, zoom   float32
+		wantX, wantY float32
+	}{
+		{"inside", 100, 100, 1, 100, 100},
+		{"past min", -50, -20, 1, 0, 0},
+		{"past max", 1500, 900, 1, 1200, 400},
+		{"zoomed out past the height", 1500, 0, 0.5, 800, 200},
+	}
+
+	for _, test := range tests {
+		c := &Camera{x: test.x, y: test.y, zoom: test.zoom}
+		cc := &CameraController{camera: c, bounded: true, maxX: 2000, maxY: 1000}
+
+		cc.clampToBounds()
+
+		if c.x != test.wantX || c.y != test.wantY {
+			t.Errorf("%s: got %v, %v, want %v, %v", test.name, c.x, c.y, test.wantX, test.wantY)
+		}
+	}
+}
+
+func TestShakeNoiseRange(t *testing.T) {
+	for seed := 0; seed < 3; seed++ {
+		for i := 0; i < 1000; i++ {
+			if n := shakeNoise(float32(i)*0.01, seed); n < -1 || n > 1 {
+				t.Errorf("seed %d, t %v: got %v, want between -1 and 1", seed, float32(i)*0.01, n)
+			}
+		}
+	}
+}
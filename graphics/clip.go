@@ -0,0 +1,286 @@
+package graphics
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Clipping rectangles, a render object with a clip rectangle is only drawn (and picked) inside it, eg for scrolling panels.
+The clip rectangle is applied with glScissor around the render objects draw and is intersected with the viewport.
+
+Clip rectangles nest, a render object is also clipped by the clip rectangles of its clip parent (eg the panel containing it)
+and of a clip node and its ancestors. Scissor rectangles are axis aligned so rotated clip rectangles clip to their bounds.
+*/
+
+var noClip = bounds{
+	float32(math.Inf(-1)),
+	float32(math.Inf(-1)),
+	float32(math.Inf(1)),
+	float32(math.Inf(1)),
+}
+
+type clipping struct {
+	// rect ... nil if the render object only uses the clip rectangles of its parent or node
+	rect   *rect
+	parent *RenderObject
+	node   *Node
+}
+
+// SetClipRect ... clip to the rectangle from x, y (top left), in screen pixels for screen space render objects and world pixels otherwise
+func (obj *RenderObject) SetClipRect(x, y, width, height float32) {
+	obj.clip.rect = &rect{x, y, width, height}
+}
+
+// ClearClipRect ... stop clipping to the render objects own clip rectangle, its parent and node still clip it
+func (obj *RenderObject) ClearClipRect() {
+	obj.clip.rect = nil
+}
+
+// SetClipParent ... also clip to the clip rectangles of parent, eg a container, nil to remove the parent
+func (obj *RenderObject) SetClipParent(parent *RenderObject) {
+	for ancestor := parent; ancestor != nil; ancestor = ancestor.clip.parent {
+		if ancestor == obj {
+			panic("Render object cannot be clipped by its own descendant")
+		}
+	}
+
+	obj.clip.parent = parent
+}
+
+// SetClipNode ... also clip to the clip rectangles of n and its ancestors, nil to remove the node
+func (obj *RenderObject) SetClipNode(n *Node) {
+	obj.clip.node = n
+}
+
+func (obj *RenderObject) clipped() bool {
+	return obj.clip.rect != nil || obj.clip.parent != nil || obj.clip.node != nil
+}
+
+// clipBounds ... bounds in view pixels the render object is clipped to when drawn to v
+func (obj *RenderObject) clipBounds(v view) bounds {
+	b := noClip
+
+	if r := obj.clip.rect; r != nil {
+		b = b.intersect(r.viewBounds(obj.toView(v)))
+	}
+
+	if obj.clip.parent != nil {
+		b = b.intersect(obj.clip.parent.clipBounds(v))
+	}
+
+	if obj.clip.node != nil {
+		b = b.intersect(obj.clip.node.clipBounds(obj.toView(v)))
+	}
+
+	return b
+}
+
+// toView ... convert the render objects pixels to view pixels when drawn to v
+func (obj *RenderObject) toView(v view) func(x, y float32) (float32, float32) {
+	if obj.screenSpace {
+		return func(x, y float32) (float32, float32) {
+			return x, y
+		}
+	}
+
+	return v.worldToView
+}
+
+/*
+Node clipping, node clip rectangles are in the nodes coordinates and are moved, rotated and scaled with it
+*/
+
+// SetClipRect ... clip render objects using the node (or its descendants) as their clip node to the rectangle
+func (n *Node) SetClipRect(x, y, width, height float32) {
+	n.clip = &rect{x, y, width, height}
+}
+
+// ClearClipRect ... stop clipping to the nodes rectangle, the clip rectangles of its ancestors still apply
+func (n *Node) ClearClipRect() {
+	n.clip = nil
+}
+
+// clipBounds ... toView converts the nodes world pixels to view pixels, they are screen pixels for screen space render objects
+func (n *Node) clipBounds(toView func(x, y float32) (float32, float32)) bounds {
+	b := noClip
+
+	for node := n; node != nil; node = node.parent {
+		if node.clip == nil {
+			continue
+		}
+
+		world := node.world
+		b = b.intersect(node.clip.viewBounds(func(x, y float32) (float32, float32) {
+			pos := world.Mul3x1(mgl32.Vec3{x, y, 1})
+
+			return toView(pos.X(), pos.Y())
+		}))
+	}
+
+	return b
+}
+
+/*
+Scissor handling
+*/
+
+// beginClip ... set the scissor to the render objects clip bounds, false if nothing would be drawn
+func (obj *RenderObject) beginClip() bool {
+	b := obj.clipBounds(drawView).
+		offset(drawRect.x, drawRect.y).
+		intersect(drawRect.viewBounds(func(x, y float32) (float32, float32) {
+			return x, y
+		}))
+
+	if b.empty() {
+		return false
+	}
+
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(outputRect(b.minX, b.minY, b.maxX-b.minX, b.maxY-b.minY))
+
+	return true
+}
+
+// endClip ... restore the scissor of the view being drawn
+func endClip() {
+	if drawingViewport {
+		gl.Scissor(outputRect(drawRect.x, drawRect.y, drawRect.width, drawRect.height))
+
+		return
+	}
+
+	gl.Disable(gl.SCISSOR_TEST)
+}
+
+// viewBounds ... bounds of the rectangles corners converted to view pixels
+func (r *rect) viewBounds(toView func(x, y float32) (float32, float32)) bounds {
+	b := emptyBounds
+
+	for _, corner := range [4][2]float32{
+		{r.x, r.y},
+		{r.x + r.width, r.y},
+		{r.x + r.width, r.y + r.height},
+		{r.x, r.y + r.height},
+	} {
+		b = b.extend(toView(corner[0], corner[1]))
+	}
+
+	return b
+}
+
+func (b bounds) intersect(other bounds) bounds {
+	return bounds{
+		float32(math.Max(float64(b.minX), float64(other.minX))),
+		float32(math.Max(float64(b.minY), float64(other.minY))),
+		float32(math.Min(float64(b.maxX), float64(other.maxX))),
+		float32(math.Min(float64(b.maxY), float64(other.maxY))),
+	}
+}
+
+func (b bounds) offset(x, y float32) bounds {
+	return bounds{b.minX + x, b.minY + y, b.maxX + x, b.maxY + y}
+}
+
+func (b bounds) contains(x, y float32) bool {
+	return x >= b.minX && y >= b.minY && x < b.maxX && y < b.maxY
+}
+
+/*
+Clip jobs
+*/
+
+func callSetClipRect(job RenderObjectJob) {
+	params := job.params
+
+	job.obj.SetClipRect(
+		params[0].(float32),
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+	)
+}
+
+func callClearClipRect(job RenderObjectJob) {
+	job.obj.ClearClipRect()
+}
+
+func callSetClipParent(job RenderObjectJob) {
+	job.obj.SetClipParent(job.params[0].(*RenderObject))
+}
+
+func callSetClipNode(job RenderObjectJob) {
+	job.obj.SetClipNode(job.params[0].(*Node))
+}
+
+func callNodeSetClipRect(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Node).SetClipRect(
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+		params[4].(float32),
+	)
+}
+
+func callNodeClearClipRect(job RenderObjectJob) {
+	job.params[0].(*Node).ClearClipRect()
+}
+
+func (obj *RenderObject) SetClipRectJob(x, y, width, height float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{x, y, width, height},
+		nil,
+		callSetClipRect,
+	}
+}
+
+func (obj *RenderObject) ClearClipRectJob() {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		nil,
+		nil,
+		callClearClipRect,
+	}
+}
+
+func (obj *RenderObject) SetClipParentJob(parent *RenderObject) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{parent},
+		nil,
+		callSetClipParent,
+	}
+}
+
+func (obj *RenderObject) SetClipNodeJob(n *Node) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{n},
+		nil,
+		callSetClipNode,
+	}
+}
+
+func (n *Node) SetClipRectJob(x, y, width, height float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{n, x, y, width, height},
+		nil,
+		callNodeSetClipRect,
+	}
+}
+
+func (n *Node) ClearClipRectJob() {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{n},
+		nil,
+		callNodeClearClipRect,
+	}
+}
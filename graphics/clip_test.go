@@ -0,0 +1,119 @@
+package graphics
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func identity(x, y float32) (float32, float32) {
+	return x, y
+}
+
+func TestBoundsIntersect(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b, want bounds
+		wantEmpty  bool
+	}{
+		{"overlapping", bounds{0, 0, 10, 10}, bounds{5, -5, 15, 5}, bounds{5, 0, 10, 5}, false},
+		{"inside", bounds{0, 0, 10, 10}, bounds{2, 3, 4, 5}, bounds{2, 3, 4, 5}, false},
+		{"no clip", noClip, bounds{2, 3, 4, 5}, bounds{2, 3, 4, 5}, false},
+		{"apart", bounds{0, 0, 10, 10}, bounds{20, 20, 30, 30}, bounds{20, 20, 10, 10}, true},
+		{"touching", bounds{0, 0, 10, 10}, bounds{10, 0, 20, 10}, bounds{10, 0, 10, 10}, true},
+	}
+
+	for _, test := range tests {
+		got := test.a.intersect(test.b)
+
+		if got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+
+		if got.empty() != test.wantEmpty {
+			t.Errorf("%s: empty %v, want %v", test.name, got.empty(), test.wantEmpty)
+		}
+	}
+}
+
+func TestBoundsOffset(t *testing.T) {
+	got := bounds{1, 2, 3, 4}.offset(10, -20)
+
+	if want := (bounds{11, -18, 13, -16}); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBoundsContains(t *testing.T) {
+	b := bounds{0, 0, 10, 20}
+
+	tests := []struct {
+		x, y float32
+		want bool
+	}{
+		{5, 5, true},
+		{0, 0, true},
+		{10, 5, false},
+		{5, 20, false},
+		{-1, 5, false},
+		{9.9, 19.9, true},
+	}
+
+	for _, test := range tests {
+		if got := b.contains(test.x, test.y); got != test.want {
+			t.Errorf("%v, %v: got %v, want %v", test.x, test.y, got, test.want)
+		}
+	}
+}
+
+func TestRectViewBounds(t *testing.T) {
+	r := &rect{10, 20, 30, 40}
+
+	tests := []struct {
+		name   string
+		toView func(x, y float32) (float32, float32)
+		want   bounds
+	}{
+		{"identity", identity, bounds{10, 20, 40, 60}},
+		{"scaled", func(x, y float32) (float32, float32) { return x * 2, y * 2 }, bounds{20, 40, 80, 120}},
+		{"swapped axis", func(x, y float32) (float32, float32) { return -y, x }, bounds{-60, 10, -20, 40}},
+	}
+
+	for _, test := range tests {
+		if got := r.viewBounds(test.toView); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRenderObjectClipBounds(t *testing.T) {
+	v := view{&Camera{x: 10, y: 20, zoom: 1}, 800, 600}
+
+	node := &Node{world: mgl32.Translate2D(100, 100)}
+	node.SetClipRect(0, 0, 50, 50)
+
+	parent := &RenderObject{}
+	parent.SetClipRect(0, 0, 120, 120)
+
+	tests := []struct {
+		name        string
+		screenSpace bool
+		clip        clipping
+		want        bounds
+	}{
+		{"world rect", false, clipping{rect: &rect{10, 20, 30, 40}}, bounds{0, 0, 30, 40}},
+		{"screen rect", true, clipping{rect: &rect{10, 20, 30, 40}}, bounds{10, 20, 40, 60}},
+		{"world parent", true, clipping{rect: &rect{100, 0, 50, 200}, parent: parent}, bounds{100, 0, 110, 100}},
+		{"world node", false, clipping{node: node}, bounds{90, 80, 140, 130}},
+		{"screen node", true, clipping{node: node}, bounds{100, 100, 150, 150}},
+		{"rect and node", true, clipping{rect: &rect{0, 0, 120, 120}, node: node}, bounds{100, 100, 120, 120}},
+	}
+
+	for _, test := range tests {
+		obj := &RenderObject{screenSpace: test.screenSpace, clip: test.clip}
+
+		if got := obj.clipBounds(v); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
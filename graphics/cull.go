@@ -0,0 +1,219 @@
+package graphics
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Viewport culling, the bounds of every shape (6 vertices) and of every chunk of cullChunkShapes shapes are kept
+up to date from the vertices modified in the VAO. Each render only the ranges of shapes overlapping the view are drawn,
+whole chunks are skipped before their shapes are tested.
+
+Bounds are kept after grouped rotation, the view is transformed back through the camera, translation and global
+rotation so the bounds don't need updating when those change.
+*/
+
+const cullChunkShapes = 64
+
+type bounds struct {
+	minX, minY, maxX, maxY float32
+}
+
+var emptyBounds = bounds{
+	float32(math.Inf(1)),
+	float32(math.Inf(1)),
+	float32(math.Inf(-1)),
+	float32(math.Inf(-1)),
+}
+
+type cullData struct {
+	disabled bool
+	shapes   []bounds
+	chunks   []bounds
+
+	// Draw ranges reused each render
+	firsts, counts []int32
+}
+
+// CullStats ... totals for a single render
+type CullStats struct {
+	Objects, CulledObjects   int
+	Vertices, CulledVertices int
+	// DrawRanges ... number of ranges drawn by all render objects
+	DrawRanges int
+}
+
+var cullStats, lastCullStats CullStats
+
+// LastCullStats ... culling totals of the last render, must be called on the main thread
+func LastCullStats() CullStats {
+	return lastCullStats
+}
+
+// SetCulling ... culling is enabled by default, it can be disabled for render objects which are always on screen
+func (obj *RenderObject) SetCulling(enabled bool) {
+	obj.cull.disabled = !enabled
+}
+
+// visibleRanges ... first vertices and vertex counts of the visible shapes
+func (obj *RenderObject) visibleRanges() (firsts, counts []int32) {
+	obj.updateBounds()
+
+	c := &obj.cull
+	c.findRanges(obj.freeVert/6, obj.viewBounds())
+
+	drawn := 0
+	for _, count := range c.counts {
+		drawn += int(count)
+	}
+
+	cullStats.Objects++
+	cullStats.Vertices += obj.freeVert
+	cullStats.CulledVertices += obj.freeVert - drawn
+	cullStats.DrawRanges += len(c.firsts)
+
+	if len(c.firsts) == 0 {
+		cullStats.CulledObjects++
+	}
+
+	return c.firsts, c.counts
+}
+
+// findRanges ... set firsts and counts to the ranges of the first shapeNum shapes overlapping view
+func (c *cullData) findRanges(shapeNum int, view bounds) {
+	c.firsts, c.counts = c.firsts[:0], c.counts[:0]
+
+	for chunk := 0; chunk*cullChunkShapes < shapeNum; chunk++ {
+		if !c.chunks[chunk].overlaps(view) {
+			continue
+		}
+
+		end := (chunk + 1) * cullChunkShapes
+		if end > shapeNum {
+			end = shapeNum
+		}
+
+		for shape := chunk * cullChunkShapes; shape < end; shape++ {
+			if !c.shapes[shape].overlaps(view) {
+				continue
+			}
+
+			// Join with the previous range if it ends at this shape
+			first := int32(shape * 6)
+			if n := len(c.firsts); n > 0 && c.firsts[n-1]+c.counts[n-1] == first {
+				c.counts[n-1] += 6
+			} else {
+				c.firsts = append(c.firsts, first)
+				c.counts = append(c.counts, 6)
+			}
+		}
+	}
+}
+
+// drawVisible ... draw only the visible shapes, the render object must have been prepared
+func (obj *RenderObject) drawVisible(firsts, counts []int32) {
+	gl.MultiDrawArrays(gl.TRIANGLES, &firsts[0], &counts[0], int32(len(firsts)))
+}
+
+// updateBounds ... recalculate the bounds of shapes modified since the last update
+func (obj *RenderObject) updateBounds() {
+	start, end := obj.vao.TakeModified()
+	obj.cull.update(start, end, obj.maxVert, obj.localVert)
+}
+
+// update ... recalculate the bounds of the shapes of vertices start to end, vert is the position of a vertex after grouped rotation
+func (c *cullData) update(start, end, maxVert int, vert func(index int) (x, y float32)) {
+	if c.shapes == nil {
+		shapeNum := (maxVert + 5) / 6
+		c.shapes = make([]bounds, shapeNum)
+		c.chunks = make([]bounds, (shapeNum+cullChunkShapes-1)/cullChunkShapes)
+	}
+
+	if start == end {
+		return
+	}
+
+	firstShape := start / 6
+	lastShape := (end - 1) / 6
+
+	for shape := firstShape; shape <= lastShape && shape < len(c.shapes); shape++ {
+		shapeBounds := emptyBounds
+
+		for v := shape * 6; v < shape*6+6 && v < maxVert; v++ {
+			shapeBounds = shapeBounds.extend(vert(v))
+		}
+
+		c.shapes[shape] = shapeBounds
+	}
+
+	for chunk := firstShape / cullChunkShapes; chunk <= lastShape/cullChunkShapes && chunk < len(c.chunks); chunk++ {
+		chunkBounds := emptyBounds
+
+		for shape := chunk * cullChunkShapes; shape < (chunk+1)*cullChunkShapes && shape < len(c.shapes); shape++ {
+			chunkBounds = chunkBounds.union(c.shapes[shape])
+		}
+
+		c.chunks[chunk] = chunkBounds
+	}
+}
+
+// viewBounds ... bounds of the screen in the render objects coordinates before translation and global rotation
+func (obj *RenderObject) viewBounds() bounds {
+	return viewBounds(drawView, obj.screenSpace, *obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr], obj.vao.Rotation())
+}
+
+// viewBounds ... bounds of v after undoing the translation and global rotation rot (x, y, cos, sin)
+func viewBounds(v view, screenSpace bool, transX, transY float32, rot mgl32.Vec4) bounds {
+	corners := [4][2]float32{
+		{0, 0},
+		{v.width, 0},
+		{v.width, v.height},
+		{0, v.height},
+	}
+
+	b := emptyBounds
+
+	for _, corner := range corners {
+		x, y := corner[0], corner[1]
+
+		if !screenSpace {
+			x, y = v.viewToWorld(x, y)
+		}
+
+		// Undo the translation then the global rotation
+		b = b.extend(rotateAbout(x-transX, y-transY, rot[0], rot[1], rot[2], -rot[3]))
+	}
+
+	return b
+}
+
+func (b bounds) extend(x, y float32) bounds {
+	return bounds{
+		float32(math.Min(float64(b.minX), float64(x))),
+		float32(math.Min(float64(b.minY), float64(y))),
+		float32(math.Max(float64(b.maxX), float64(x))),
+		float32(math.Max(float64(b.maxY), float64(y))),
+	}
+}
+
+func (b bounds) union(other bounds) bounds {
+	if other.empty() {
+		return b
+	}
+
+	return b.extend(other.minX, other.minY).extend(other.maxX, other.maxY)
+}
+
+// empty ... bounds with no area draw nothing, eg cleared shapes
+func (b bounds) empty() bool {
+	return b.minX >= b.maxX || b.minY >= b.maxY
+}
+
+func (b bounds) overlaps(other bounds) bool {
+	return !b.empty() &&
+		b.minX < other.maxX && b.maxX > other.minX &&
+		b.minY < other.maxY && b.maxY > other.minY
+}
@@ -0,0 +1,195 @@
+package graphics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBoundsExtend(t *testing.T) {
+	tests := []struct {
+		name      string
+		b         bounds
+		points    [][2]float32
+		want      bounds
+		wantEmpty bool
+	}{
+		{"no points", emptyBounds, nil, emptyBounds, true},
+		{"single point", emptyBounds, [][2]float32{{3, 4}}, bounds{3, 4, 3, 4}, true},
+		{"points", emptyBounds, [][2]float32{{3, 4}, {-1, 8}, {2, 2}}, bounds{-1, 2, 3, 8}, false},
+		{"inside", bounds{0, 0, 10, 10}, [][2]float32{{5, 5}}, bounds{0, 0, 10, 10}, false},
+	}
+
+	for _, test := range tests {
+		got := test.b
+
+		for _, p := range test.points {
+			got = got.extend(p[0], p[1])
+		}
+
+		if got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+
+		if got.empty() != test.wantEmpty {
+			t.Errorf("%s: empty %v, want %v", test.name, got.empty(), test.wantEmpty)
+		}
+	}
+}
+
+func TestBoundsUnion(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b, want bounds
+	}{
+		{"apart", bounds{0, 0, 10, 10}, bounds{20, -5, 30, 5}, bounds{0, -5, 30, 10}},
+		{"inside", bounds{0, 0, 10, 10}, bounds{2, 2, 4, 4}, bounds{0, 0, 10, 10}},
+		{"with empty", bounds{0, 0, 10, 10}, emptyBounds, bounds{0, 0, 10, 10}},
+		{"cleared shape", bounds{5, 5, 10, 10}, bounds{}, bounds{5, 5, 10, 10}},
+		{"from empty", emptyBounds, bounds{1, 2, 3, 4}, bounds{1, 2, 3, 4}},
+	}
+
+	for _, test := range tests {
+		if got := test.a.union(test.b); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestBoundsOverlaps(t *testing.T) {
+	view := bounds{0, 0, 800, 600}
+
+	tests := []struct {
+		name string
+		b    bounds
+		want bool
+	}{
+		{"inside", bounds{10, 10, 20, 20}, true},
+		{"across edge", bounds{790, 590, 810, 610}, true},
+		{"touching", bounds{800, 0, 810, 10}, false},
+		{"left", bounds{-20, 0, -10, 10}, false},
+		{"below", bounds{0, 700, 10, 710}, false},
+		{"cleared shape", bounds{}, false},
+		{"empty", emptyBounds, false},
+	}
+
+	for _, test := range tests {
+		if got := test.b.overlaps(view); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCullUpdate(t *testing.T) {
+	verts := append(append(rectVerts(0, 0, 10, 10), rectVerts(20, 0, 10, 10)...), rectVerts(40, 0, 10, 10)...)
+	moved := append(append(rectVerts(0, 0, 10, 10), rectVerts(120, 0, 10, 10)...), rectVerts(140, 0, 10, 10)...)
+	cleared := append(append(rectVerts(0, 0, 10, 10), make([]float32, 12)...), rectVerts(40, 0, 10, 10)...)
+
+	// Grouped rotation of 90 degrees about 0, 0
+	rotated := func(index int) (x, y float32) {
+		return rotateAbout(verts[index*2], verts[index*2+1], 0, 0, 0, 1)
+	}
+
+	tests := []struct {
+		name       string
+		start, end int
+		vert       func(index int) (x, y float32)
+		wantShapes []bounds
+		wantChunk  bounds
+	}{
+		{"all", 0, 18, vertsOf(verts), []bounds{{0, 0, 10, 10}, {20, 0, 30, 10}, {40, 0, 50, 10}}, bounds{0, 0, 50, 10}},
+		{"nothing modified", 5, 5, vertsOf(moved), []bounds{{0, 0, 10, 10}, {20, 0, 30, 10}, {40, 0, 50, 10}}, bounds{0, 0, 50, 10}},
+		{"single vertex", 7, 8, vertsOf(moved), []bounds{{0, 0, 10, 10}, {120, 0, 130, 10}, {40, 0, 50, 10}}, bounds{0, 0, 130, 10}},
+		{"across shapes", 5, 13, vertsOf(moved), []bounds{{0, 0, 10, 10}, {120, 0, 130, 10}, {140, 0, 150, 10}}, bounds{0, 0, 150, 10}},
+		{"cleared", 6, 12, vertsOf(cleared), []bounds{{0, 0, 10, 10}, {0, 0, 0, 0}, {140, 0, 150, 10}}, bounds{0, 0, 150, 10}},
+		{"rotated", 0, 18, rotated, []bounds{{-10, 0, 0, 10}, {-10, 20, 0, 30}, {-10, 40, 0, 50}}, bounds{-10, 0, 0, 50}},
+	}
+
+	// Each update is applied to the bounds left by the previous one
+	var c cullData
+
+	for _, test := range tests {
+		c.update(test.start, test.end, 18, test.vert)
+
+		if !reflect.DeepEqual(c.shapes, test.wantShapes) {
+			t.Errorf("%s: got shapes %v, want %v", test.name, c.shapes, test.wantShapes)
+		}
+
+		if len(c.chunks) != 1 || c.chunks[0] != test.wantChunk {
+			t.Errorf("%s: got chunks %v, want %v", test.name, c.chunks, test.wantChunk)
+		}
+	}
+}
+
+func TestCullChunks(t *testing.T) {
+	var c cullData
+
+	// The last shape is in the second chunk
+	c.update(0, (cullChunkShapes+1)*6, (cullChunkShapes+1)*6, func(index int) (x, y float32) {
+		return float32(index/6 + index%2), float32(index % 2)
+	})
+
+	want := []bounds{{0, 0, cullChunkShapes, 1}, {cullChunkShapes, 0, cullChunkShapes + 1, 1}}
+
+	if !reflect.DeepEqual(c.chunks, want) {
+		t.Errorf("got %v, want %v", c.chunks, want)
+	}
+}
+
+func TestViewBounds(t *testing.T) {
+	unrotated := mgl32.Vec4{0, 0, 1, 0}
+
+	tests := []struct {
+		name           string
+		camera         *Camera
+		screenSpace    bool
+		transX, transY float32
+		rot            mgl32.Vec4
+		want           bounds
+	}{
+		{"camera", &Camera{x: 100, y: 50, zoom: 1}, false, 0, 0, unrotated, bounds{100, 50, 900, 650}},
+		{"zoomed", &Camera{x: 100, y: 50, zoom: 2}, false, 0, 0, unrotated, bounds{300, 200, 700, 500}},
+		{"screen space", &Camera{x: 100, y: 50, zoom: 2}, true, 0, 0, unrotated, bounds{0, 0, 800, 600}},
+		{"translated", &Camera{zoom: 1}, false, 10, 20, unrotated, bounds{-10, -20, 790, 580}},
+		{"rotated", &Camera{zoom: 1}, true, 0, 0, mgl32.Vec4{0, 0, 0, 1}, bounds{0, -800, 600, 0}},
+		{"rotated about a point", &Camera{zoom: 1}, true, 0, 0, mgl32.Vec4{400, 300, -1, 0}, bounds{0, 0, 800, 600}},
+	}
+
+	for _, test := range tests {
+		got := viewBounds(view{test.camera, 800, 600}, test.screenSpace, test.transX, test.transY, test.rot)
+
+		if got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFindRanges(t *testing.T) {
+	view := bounds{0, 0, 800, 600}
+	on, off := bounds{10, 10, 20, 20}, bounds{-20, 0, -10, 10}
+
+	tests := []struct {
+		name                   string
+		shapes                 []bounds
+		chunk                  bounds
+		shapeNum               int
+		wantFirsts, wantCounts []int32
+	}{
+		{"all visible", []bounds{on, on, on}, on, 3, []int32{0}, []int32{18}},
+		{"middle culled", []bounds{on, off, on}, on, 3, []int32{0, 12}, []int32{6, 6}},
+		{"ends culled", []bounds{off, on, on, off}, on, 4, []int32{6}, []int32{12}},
+		{"all culled", []bounds{off, off}, off, 2, []int32{}, []int32{}},
+		{"chunk culled", []bounds{on, on}, off, 2, []int32{}, []int32{}},
+		{"unused shapes", []bounds{on, on, on}, on, 1, []int32{0}, []int32{6}},
+	}
+
+	for _, test := range tests {
+		c := cullData{shapes: test.shapes, chunks: []bounds{test.chunk}, firsts: []int32{}, counts: []int32{}}
+		c.findRanges(test.shapeNum, view)
+
+		if !reflect.DeepEqual(c.firsts, test.wantFirsts) || !reflect.DeepEqual(c.counts, test.wantCounts) {
+			t.Errorf("%s: got %v %v, want %v %v", test.name, c.firsts, c.counts, test.wantFirsts, test.wantCounts)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Immediate mode debug drawing, shapes can be drawn from any go routine without creating render objects.
+Shapes are collected until the next render, drawn in world space on top of everything else and then discarded,
+so they must be drawn again every frame they should be visible.
+
+Lines are drawn 1 pixel wide on the screen whatever the camera zoom.
+*/
+
+const (
+	debugTexture        = "gopengl/debug"
+	debugCircleSegments = 32
+	debugInitialQuads   = 1024
+)
+
+type debugKind int
+
+const (
+	debugLine debugKind = iota
+	debugText
+)
+
+type debugShape struct {
+	kind           debugKind
+	x1, y1, x2, y2 float32
+	text           string
+	size           float32
+	colour         mgl32.Vec4
+}
+
+var (
+	debugMutex   sync.Mutex
+	debugEnabled = true
+	debugShapes  []debugShape
+	debugFont    TextFont
+
+	// Only used on the main thread
+	debugDrawing           []debugShape
+	debugObj, debugTextObj *RenderObject
+	debugVerts, debugTexs  []float32
+	debugColours           []float32
+)
+
+// SetDebugDraw ... enable or disable all debug drawing, shapes drawn while disabled are discarded
+func SetDebugDraw(enabled bool) {
+	debugMutex.Lock()
+	defer debugMutex.Unlock()
+
+	debugEnabled = enabled
+	debugShapes = debugShapes[:0]
+}
+
+// SetDebugFont ... font used by DebugText
+func SetDebugFont(f TextFont) {
+	debugMutex.Lock()
+	defer debugMutex.Unlock()
+
+	debugFont = f
+}
+
+// DebugLine ... line between two world positions
+func DebugLine(x1, y1, x2, y2 float32, colour mgl32.Vec4) {
+	addDebugShapes(debugShape{kind: debugLine, x1: x1, y1: y1, x2: x2, y2: y2, colour: colour})
+}
+
+// DebugRect ... outline of a rectangle from its top left
+func DebugRect(x, y, width, height float32, colour mgl32.Vec4) {
+	addDebugShapes(
+		debugShape{kind: debugLine, x1: x, y1: y, x2: x + width, y2: y, colour: colour},
+		debugShape{kind: debugLine, x1: x + width, y1: y, x2: x + width, y2: y + height, colour: colour},
+		debugShape{kind: debugLine, x1: x + width, y1: y + height, x2: x, y2: y + height, colour: colour},
+		debugShape{kind: debugLine, x1: x, y1: y + height, x2: x, y2: y, colour: colour},
+	)
+}
+
+// DebugCircle ... outline of a circle about x, y
+func DebugCircle(x, y, radius float32, colour mgl32.Vec4) {
+	shapes := make([]debugShape, debugCircleSegments)
+
+	for i := range shapes {
+		a1 := 2 * math.Pi * float64(i) / debugCircleSegments
+		a2 := 2 * math.Pi * float64(i+1) / debugCircleSegments
+
+		shapes[i] = debugShape{
+			kind:   debugLine,
+			x1:     x + radius*float32(math.Cos(a1)),
+			y1:     y + radius*float32(math.Sin(a1)),
+			x2:     x + radius*float32(math.Cos(a2)),
+			y2:     y + radius*float32(math.Sin(a2)),
+			colour: colour,
+		}
+	}
+
+	addDebugShapes(shapes...)
+}
+
+// DebugText ... a line of text with its top left at x, y, SetDebugFont must have been called
+func DebugText(x, y float32, text string, size float32, colour mgl32.Vec4) {
+	addDebugShapes(debugShape{kind: debugText, x1: x, y1: y, text: text, size: size, colour: colour})
+}
+
+func addDebugShapes(shapes ...debugShape) {
+	debugMutex.Lock()
+	defer debugMutex.Unlock()
+
+	if !debugEnabled {
+		return
+	}
+
+	for _, shape := range shapes {
+		if shape.kind == debugText && debugFont == nil {
+			panic("No debug font set, use SetDebugFont")
+		}
+	}
+
+	debugShapes = append(debugShapes, shapes...)
+}
+
+/*
+Debug rendering, performed after every render object has been drawn
+*/
+
+func renderDebug() {
+	debugMutex.Lock()
+	// Swap the lists so neither needs reallocating each frame
+	debugDrawing, debugShapes = debugShapes, debugDrawing[:0]
+	font := debugFont
+	debugMutex.Unlock()
+
+	if len(debugDrawing) == 0 {
+		return
+	}
+
+	drawView = windowView(camera)
+	lineWidth := 1 / camera.zoom
+	quads, glyphs := 0, 0
+
+	for _, shape := range debugDrawing {
+		if shape.kind == debugText {
+			glyphs += len([]rune(shape.text))
+		} else {
+			quads++
+		}
+	}
+
+	if quads > 0 {
+		renderDebugLines(quads, lineWidth)
+	}
+
+	if glyphs > 0 {
+		renderDebugText(glyphs, font)
+	}
+}
+
+func renderDebugLines(quads int, lineWidth float32) {
+	if debugObj == nil || debugObj.maxVert < quads*6 {
+		debugObj = growRenderObject(debugObj, quads, debugInitialQuads, createDebugTexture().File())
+		debugVerts = make([]float32, debugObj.maxVert*opengl.DEFAULT_VECTOR_SIZE)
+		debugTexs = make([]float32, debugObj.maxVert*opengl.DEFAULT_TEXS_SIZE)
+		debugColours = make([]float32, debugObj.maxVert*opengl.DEFAULT_COLOUR_SIZE)
+	}
+
+	vert := 0
+
+	for _, shape := range debugDrawing {
+		if shape.kind != debugLine {
+			continue
+		}
+
+		// Offset either side of the line by half its width
+		dx, dy := shape.x2-shape.x1, shape.y2-shape.y1
+		length := float32(math.Hypot(float64(dx), float64(dy)))
+		if length == 0 {
+			length = 1
+		}
+
+		nx, ny := -dy/length*lineWidth/2, dx/length*lineWidth/2
+
+		copy(debugVerts[vert*2:], []float32{
+			shape.x1 + nx, shape.y1 + ny,
+			shape.x2 + nx, shape.y2 + ny,
+			shape.x2 - nx, shape.y2 - ny,
+
+			shape.x1 + nx, shape.y1 + ny,
+			shape.x2 - nx, shape.y2 - ny,
+			shape.x1 - nx, shape.y1 - ny,
+		})
+
+		for i := 0; i < 6; i++ {
+			// Centre of the white texture
+			debugTexs[(vert+i)*2], debugTexs[(vert+i)*2+1] = 0.5, 0.5
+			copy(debugColours[(vert+i)*opengl.DEFAULT_COLOUR_SIZE:], shape.colour[:])
+		}
+
+		vert += 6
+	}
+
+	vao := debugObj.vao
+	vao.UpdateVertBufferIndex(0, debugVerts[:vert*opengl.DEFAULT_VECTOR_SIZE])
+	vao.UpdateTexBufferIndex(0, debugTexs[:vert*opengl.DEFAULT_TEXS_SIZE])
+	vao.UpdateColourBufferIndex(0, debugColours[:vert*opengl.DEFAULT_COLOUR_SIZE])
+	debugObj.freeVert = vert
+
+	debugObj.Render()
+}
+
+func renderDebugText(glyphs int, font TextFont) {
+	if debugTextObj == nil || debugTextObj.font != font || debugTextObj.maxVert < glyphs*6 {
+		debugTextObj = growRenderObject(debugTextObj, glyphs, debugInitialQuads, font.Texture().File())
+		debugTextObj.font = font
+	}
+
+	debugTextObj.Clear()
+
+	for _, shape := range debugDrawing {
+		if shape.kind == debugText {
+			debugTextObj.AddText(shape.x1, shape.y1, shape.text, shape.size, shape.colour)
+		}
+	}
+
+	debugTextObj.Render()
+}
+
+func deleteDebugObjects() {
+	for _, obj := range []*RenderObject{debugObj, debugTextObj} {
+		if obj != nil {
+			obj.Delete()
+		}
+	}
+
+	debugObj, debugTextObj = nil, nil
+}
+
+// createDebugTexture ... a single white pixel
+func createDebugTexture() *opengl.Texture {
+	if texture := opengl.FindTex(debugTexture); texture != nil {
+		return texture
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.White)
+
+	return opengl.CreateTexture(debugTexture, img)
+}
@@ -0,0 +1,334 @@
+package graphics
+
+import (
+	"Gopengl/util"
+	"fmt"
+	"gopengl/graphics/opengl"
+	"image"
+	"image/draw"
+	"io/ioutil"
+	"unicode"
+	"unsafe"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+/*
+TrueType font handling, glyphs are rasterized into a single atlas texture which a render object uses as its texture.
+Printable ASCII is rasterized on load, any other glyphs are added to the atlas the first time they are drawn.
+*/
+
+// TextFont ... fonts usable by text render objects, implemented by Font and BMFont
+type TextFont interface {
+	Texture() *opengl.Texture
+	// Size ... pixel height the glyphs are stored at
+	Size() float32
+	LineHeight() float32
+	// Ascent ... distance from the top of a line to its baseline
+	Ascent() float32
+	glyph(r rune) (glyph, bool)
+	kern(r0, r1 rune) float32
+}
+
+const (
+	fontAtlasSize    = 1024
+	fontAtlasPadding = 1
+)
+
+type Font struct {
+	face       font.Face
+	file       string
+	size       float32
+	ascent     float32
+	lineHeight float32
+	pixels     *image.RGBA
+	atlas      *opengl.Texture
+	glyphs     map[rune]glyph
+
+	// Atlas packing position
+	penX, penY, rowHeight int
+}
+
+// glyph ... atlas rectangle of a glyph and its offset from the pen position on the baseline
+type glyph struct {
+	rect             TexRect
+	offsetX, offsetY float32
+	advance          float32
+}
+
+var fontCount = 0
+
+// LoadFont ... load a .ttf or .otf file, size is the pixel height glyphs are rasterized at
+func LoadFont(file string, size float32) *Font {
+	f := &Font{}
+	f.load(file, size)
+
+	return f
+}
+
+func (f *Font) load(file string, size float32) {
+	data, err := ioutil.ReadFile(util.RelativePath(file))
+	if err != nil {
+		panic(fmt.Errorf("font %q not found on disk: %v", file, err))
+	}
+
+	parsed, err := opentype.Parse(data)
+	if err != nil {
+		panic(fmt.Errorf("Font parse error, error: %v", err))
+	}
+
+	face, err := opentype.NewFace(parsed, &opentype.FaceOptions{
+		Size:    float64(size),
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		panic(fmt.Errorf("Font face error, error: %v", err))
+	}
+
+	metrics := face.Metrics()
+
+	f.face = face
+	f.file = file
+	f.size = size
+	f.ascent = fixedToFloat(metrics.Ascent)
+	f.lineHeight = fixedToFloat(metrics.Height)
+	f.pixels = image.NewRGBA(image.Rect(0, 0, fontAtlasSize, fontAtlasSize))
+	f.glyphs = make(map[rune]glyph)
+	f.penX = fontAtlasPadding
+	f.penY = fontAtlasPadding
+
+	for r := rune(' '); r <= '~'; r++ {
+		f.glyph(r)
+	}
+
+	// Each atlas is unique so give it a unique texture name
+	fontCount++
+	f.atlas = opengl.CreateTexture(fmt.Sprintf("%s@%v#%d", file, size, fontCount), f.pixels)
+}
+
+func (f *Font) Size() float32 {
+	return f.size
+}
+
+func (f *Font) LineHeight() float32 {
+	return f.lineHeight
+}
+
+func (f *Font) Ascent() float32 {
+	return f.ascent
+}
+
+func (f *Font) Texture() *opengl.Texture {
+	return f.atlas
+}
+
+// glyph ... find a glyph, rasterizing it into the atlas if it has not been used yet
+func (f *Font) glyph(r rune) (glyph, bool) {
+	if g, exists := f.glyphs[r]; exists {
+		return g, true
+	}
+
+	dr, mask, maskp, advance, ok := f.face.Glyph(fixed.Point26_6{}, r)
+	if !ok {
+		return glyph{}, false
+	}
+
+	width, height := dr.Dx(), dr.Dy()
+
+	if f.penX+width+fontAtlasPadding > fontAtlasSize {
+		f.penX = fontAtlasPadding
+		f.penY += f.rowHeight + fontAtlasPadding
+		f.rowHeight = 0
+	}
+
+	if f.penY+height+fontAtlasPadding > fontAtlasSize {
+		panic(fmt.Errorf("font atlas for %q is full", f.file))
+	}
+
+	dst := image.Rect(f.penX, f.penY, f.penX+width, f.penY+height)
+	draw.DrawMask(f.pixels, dst, image.White, image.Point{}, mask, maskp, draw.Over)
+
+	// Glyphs added after loading need uploading
+	if f.atlas != nil {
+		f.atlas.SetSubImage(f.penX, f.penY, f.pixels.SubImage(dst))
+	}
+
+	g := glyph{
+		TexRect{float32(f.penX), float32(f.penY), float32(width), float32(height)},
+		float32(dr.Min.X),
+		float32(dr.Min.Y),
+		fixedToFloat(advance),
+	}
+
+	f.glyphs[r] = g
+	f.penX += width + fontAtlasPadding
+
+	if height > f.rowHeight {
+		f.rowHeight = height
+	}
+
+	return g, true
+}
+
+func (f *Font) kern(r0, r1 rune) float32 {
+	return fixedToFloat(f.face.Kern(r0, r1))
+}
+
+/*
+Text render objects
+*/
+
+// CreateTextRenderObject ... create a render object that uses the fonts texture as its texture
+func CreateTextRenderObject(obj *RenderObject, size int, f TextFont, defaultShader bool) {
+	CreateRenderObject(obj, size, f.Texture().File(), defaultShader)
+
+	obj.font = f
+}
+
+/*
+AddText ... add a line of text with its top left at x, y, size is the pixel height to draw the text at.
+Returns the index of the first vertex, each visible glyph uses 6 vertices.
+*/
+func (obj *RenderObject) AddText(x, y float32, text string, size float32, colour mgl32.Vec4) int {
+	if obj.font == nil {
+		panic("Render object has no font")
+	}
+
+	f := obj.font
+	scale := size / f.Size()
+	penX := x
+	baseline := y + f.Ascent()*scale
+	start := obj.freeVert
+	prev := rune(-1)
+
+	// Ranging over a string decodes UTF-8, invalid bytes are given as unicode.ReplacementChar
+	for _, r := range text {
+		if r == '\n' {
+			penX = x
+			baseline += f.LineHeight() * scale
+			prev = -1
+
+			continue
+		}
+
+		g, ok := f.glyph(r)
+		if !ok {
+			if g, ok = f.glyph(unicode.ReplacementChar); !ok {
+				continue
+			}
+		}
+
+		if prev >= 0 {
+			penX += f.kern(prev, r) * scale
+		}
+
+		obj.addGlyph(penX, baseline, g, scale, colour)
+
+		penX += g.advance * scale
+		prev = r
+	}
+
+	return start
+}
+
+func (obj *RenderObject) addGlyph(penX, baseline float32, g glyph, scale float32, colour mgl32.Vec4) {
+	// Spaces and other empty glyphs only advance the pen
+	if g.rect.Width == 0 || g.rect.Height == 0 {
+		return
+	}
+
+	index := obj.AddRect(
+		penX+g.offsetX*scale,
+		baseline+g.offsetY*scale,
+		g.rect.X,
+		g.rect.Y,
+		g.rect.Width*scale,
+		g.rect.Height*scale,
+		g.rect.Width,
+		g.rect.Height,
+	)
+
+	obj.SetColour(index, 6, colour)
+}
+
+func fixedToFloat(x fixed.Int26_6) float32 {
+	return float32(x) / 64
+}
+
+/*
+Text jobs
+*/
+
+func callLoadFont(job RenderObjectJob) {
+	params := job.params
+
+	(*Font)(job.retVal).load(
+		params[0].(string),
+		params[1].(float32),
+	)
+}
+
+func callCreateTextRenderObject(job RenderObjectJob) {
+	params := job.params
+
+	CreateTextRenderObject(
+		job.obj,
+		params[0].(int),
+		params[1].(TextFont),
+		params[2].(bool),
+	)
+}
+
+func callAddText(job RenderObjectJob) {
+	params := job.params
+
+	index := job.obj.AddText(
+		params[0].(float32),
+		params[1].(float32),
+		params[2].(string),
+		params[3].(float32),
+		params[4].(mgl32.Vec4),
+	)
+
+	*(*int)(job.retVal) = index
+}
+
+// LoadFontJob ... the returned font is loaded once the job has been performed, it can be passed to other jobs immediately
+func LoadFontJob(file string, size float32) *Font {
+	f := &Font{}
+
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{file, size},
+		unsafe.Pointer(f),
+		callLoadFont,
+	}
+
+	return f
+}
+
+func CreateTextRenderObjectJob(ro *RenderObject, size int, f TextFont, defaultShader bool) {
+	RenderObjectQueue <- RenderObjectJob{
+		ro,
+		[]interface{}{size, f, defaultShader},
+		nil,
+		callCreateTextRenderObject,
+	}
+}
+
+func (obj *RenderObject) AddTextJob(x, y float32, text string, size float32, colour mgl32.Vec4) *int {
+	index := 0
+
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{x, y, text, size, colour},
+		unsafe.Pointer(&index),
+		callAddText,
+	}
+
+	return &index
+}
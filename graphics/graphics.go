@@ -2,9 +2,11 @@ package graphics
 
 import (
 	"gopengl/graphics/opengl"
+	"sort"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 /*
@@ -25,14 +27,90 @@ func Init() {
 	if err != nil {
 		panic(err)
 	}
+
+	// Textures are loaded with premultiplied alpha
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
 }
 
+// windowWidth, windowHeight ... size of the view in pixels, positions and the mouse position are in these pixels
 var windowWidth float32 = 800
 var windowHeight float32 = 600
 
+// SetWindowSize ... set the size the application is designed for, how it fits the window depends on the scale policy
 func SetWindowSize(width, height float32) {
-	windowWidth = width
-	windowHeight = height
+	designWidth = width
+	designHeight = height
+	applyScalePolicy()
+}
+
+/*
+Clearing
+*/
+
+type ClearFlag uint32
+
+const (
+	ClearColour  ClearFlag = gl.COLOR_BUFFER_BIT
+	ClearDepth   ClearFlag = gl.DEPTH_BUFFER_BIT
+	ClearStencil ClearFlag = gl.STENCIL_BUFFER_BIT
+)
+
+// SetClearColor ... colour the window is cleared to before each render, opaque black by default
+func SetClearColor(r, g, b, a float32) {
+	opengl.SetClearColor(r, g, b, a)
+}
+
+// SetClearFlags ... buffers cleared before each render, without ClearColour previous frames are drawn over (eg for trails)
+func SetClearFlags(flags ClearFlag) {
+	opengl.SetClearMask(uint32(flags))
+}
+
+/*
+Texture flags
+*/
+
+// TexFlag ... permutations of the texture coordinates of a rectangle, flags can be combined
+type TexFlag uint8
+
+const (
+	// FlipX ... mirror the texture horizontally
+	FlipX TexFlag = 1 << iota
+	// FlipY ... mirror the texture vertically
+	FlipY
+	/*
+		Rotate90 ... the region is stored rotated 90 degrees clockwise, as in packed atlases, and is drawn upright.
+		The texture rectangle is the region as stored in the texture, flips are performed after the rotation.
+	*/
+	Rotate90
+)
+
+func combineTexFlags(flags []TexFlag) TexFlag {
+	var combined TexFlag
+	for _, flag := range flags {
+		combined |= flag
+	}
+
+	return combined
+}
+
+// applyTexFlags ... permute the texture coordinates of a rectangle from AddRect
+func applyTexFlags(texs []float32, combined TexFlag) []float32 {
+	// Undoing a clockwise rotation is a diagonal flip followed by a vertical flip
+	var flip TileFlip
+	if combined&Rotate90 != 0 {
+		flip = FlipDiagonal | FlipVertical
+	}
+
+	if combined&FlipX != 0 {
+		flip ^= FlipHorizontal
+	}
+
+	if combined&FlipY != 0 {
+		flip ^= FlipVertical
+	}
+
+	return flipRectTexs(texs, flip)
 }
 
 /*
@@ -45,11 +123,27 @@ General transformations can be applied to the entire render object which are per
 */
 
 type RenderObject struct {
-	vao      *opengl.VAO
-	texture  *opengl.Texture
-	freeVert int
-	maxVert  int
-	ptrVars  []*float32
+	vao        *opengl.VAO
+	texture    *opengl.Texture
+	freeVert   int
+	maxVert    int
+	ptrVars    []*float32
+	animations map[int]*Animation
+	font       TextFont
+	nineSlices map[int]nineSlice
+	// screenSpace ... drawn in screen pixels, ignoring the camera
+	screenSpace bool
+	// layer ... render objects on higher layers are drawn on top
+	layer int
+	cull  cullData
+	// hidden ... vertices of hidden shapes by index, restored when shown
+	hidden    map[int][]float32
+	invisible bool
+	// texFlags ... texture flags of rectangles by index, reapplied when their texture changes
+	texFlags map[int]TexFlag
+	// instanced ... instance data, nil unless created with CreateInstancedRenderObject
+	instanced *instancing
+	clip      clipping
 }
 
 var renderObjects = make([]*RenderObject, 0)
@@ -59,6 +153,10 @@ var window *glfw.Window
 
 func SetWindow(newWindow *glfw.Window) {
 	window = newWindow
+	window.SetFramebufferSizeCallback(onFramebufferResize)
+	window.SetSizeCallback(onWindowResize)
+
+	resizeWindow(window)
 }
 
 func (ro *RenderObject) Vao() *opengl.VAO {
@@ -67,6 +165,14 @@ func (ro *RenderObject) Vao() *opengl.VAO {
 }
 
 func CreateRenderObject(obj *RenderObject, size int, texture string, defaultShader bool) {
+	initRenderObject(obj, size, texture, defaultShader)
+
+	renderObjects = append(renderObjects, obj)
+	sortRenderObjects()
+}
+
+// initRenderObject ... create the render objects vao without adding it to the render objects drawn each render
+func initRenderObject(obj *RenderObject, size int, texture string, defaultShader bool) {
 	vao := opengl.CreateVAO(uint32(size), texture, defaultShader, windowWidth, windowHeight)
 	vao.CreateBuffers()
 
@@ -77,14 +183,37 @@ func CreateRenderObject(obj *RenderObject, size int, texture string, defaultShad
 
 	// Init pointer vars
 	obj.InitPointers()
+}
 
-	renderObjects = append(renderObjects, obj)
+/*
+growRenderObject ... recreate a render object which is drawn directly rather than with the other render objects,
+its capacity is doubled from initialQuads (or its previous capacity) until quads fit. obj may be nil.
+*/
+func growRenderObject(obj *RenderObject, quads, initialQuads int, texture string) *RenderObject {
+	capacity := initialQuads
+	if obj != nil {
+		capacity = obj.maxVert / 6
+		obj.Delete()
+	}
+
+	for capacity < quads {
+		capacity *= 2
+	}
+
+	obj = &RenderObject{}
+	initRenderObject(obj, capacity*6, texture, true)
+	obj.SetCulling(false)
+
+	return obj
 }
 
 func DeleteRenderObjects() {
 	for _, obj := range renderObjects {
 		obj.Delete()
 	}
+
+	deleteDebugObjects()
+	deleteBatchObjects()
 }
 
 /*
@@ -92,18 +221,60 @@ Render Object methods
 */
 
 func Render() {
-	gl.ClearColor(0.0, 0.0, 0.0, 1.0)
-	gl.Clear(gl.COLOR_BUFFER_BIT)
-	for _, obj := range renderObjects {
-		obj.Render()
-	}
+	opengl.Clear()
+	updateAnimations()
+	updateParticleSystems()
+	updateNodes()
+	updateCameraControllers()
+
+	cullStats = CullStats{}
+	batchStats = BatchStats{}
+
+	renderViewports()
+	renderDebug()
+
+	lastCullStats = cullStats
+	lastBatchStats = batchStats
 
 	Poll(window)
 }
 
+// Render ... only vertices up to the last added shape are drawn, shapes outside of the view are culled
 func (obj *RenderObject) Render() {
-	vertNum := obj.PrepRender()
-	gl.DrawArrays(gl.TRIANGLES, 0, vertNum)
+	if obj.invisible {
+		return
+	}
+
+	if obj.clipped() {
+		if !obj.beginClip() {
+			return
+		}
+
+		defer endClip()
+	}
+
+	if obj.instanced != nil {
+		obj.renderInstances()
+
+		return
+	}
+
+	if obj.cull.disabled {
+		obj.PrepRender()
+		gl.DrawArrays(gl.TRIANGLES, 0, int32(obj.freeVert))
+		obj.FinishRender()
+
+		return
+	}
+
+	firsts, counts := obj.visibleRanges()
+
+	if len(firsts) == 0 {
+		return
+	}
+
+	obj.PrepRender()
+	obj.drawVisible(firsts, counts)
 	obj.FinishRender()
 }
 
@@ -117,12 +288,20 @@ func (obj *RenderObject) FinishRender() {
 }
 
 func (obj *RenderObject) Delete() {
+	if obj.instanced != nil {
+		obj.instanced.vao.Delete()
+
+		return
+	}
+
 	obj.vao.Delete()
 }
 
 // AddSquare ... add a square to the render object, position is from the top left in pixels
 // Returns index of new objects first vertex
 func (obj *RenderObject) AddSquare(x, y, xTex, yTex, width, widthTex float32) int {
+	obj.checkShapes()
+
 	verts := []float32{
 		// Upper right triangle
 		x, y,
@@ -164,7 +343,10 @@ func (obj *RenderObject) AddSquare(x, y, xTex, yTex, width, widthTex float32) in
 // 	return obj.AddRect(x, y, xTex, yTex, width, width, widthTex, widthTex)
 // }
 
-func (obj *RenderObject) AddRect(x, y, xTex, yTex, width, height, widthTex, heightTex float32) int {
+// AddRect ... flags permute the texture coordinates, eg to mirror the texture or draw a region stored rotated
+func (obj *RenderObject) AddRect(x, y, xTex, yTex, width, height, widthTex, heightTex float32, flags ...TexFlag) int {
+	obj.checkShapes()
+
 	verts := []float32{
 		// Upper right triangle
 		x, y,
@@ -191,12 +373,13 @@ func (obj *RenderObject) AddRect(x, y, xTex, yTex, width, height, widthTex, heig
 
 	// Removed as vertex scaling performed in shader now.
 	// verts = PixToScreen(verts)
-	texs = obj.texture.PixToTex(texs)
-
 	if obj.freeVert+6 > obj.maxVert {
 		panic("Render Object Buffer overflow")
 	}
 
+	obj.setTexFlags(obj.freeVert, combineTexFlags(flags))
+	texs = obj.texture.PixToTex(applyTexFlags(texs, obj.texFlags[obj.freeVert]))
+
 	obj.vao.UpdateBufferIndex(obj.freeVert, verts, texs)
 	obj.freeVert += 6
 
@@ -208,6 +391,8 @@ func (obj *RenderObject) ModifyVertSquare(index int, x, y, width float32) {
 }
 
 func (obj *RenderObject) ModifyVertRect(index int, x, y, width, height float32) {
+	obj.checkShapes()
+
 	verts := []float32{
 		// Upper right triangle
 		x, y,
@@ -217,7 +402,7 @@ func (obj *RenderObject) ModifyVertRect(index int, x, y, width, height float32)
 		// Lower left triangle
 		x, y,
 		x + width, y + height,
-		x, y - height,
+		x, y + height,
 	}
 
 	// verts = PixToScreen(verts)
@@ -233,7 +418,13 @@ func (obj *RenderObject) ModifyTexSquare(index int, xTex, yTex, widthTex float32
 	obj.ModifyTexRect(index, xTex, yTex, widthTex, widthTex)
 }
 
-func (obj *RenderObject) ModifyTexRect(index int, xTex, yTex, widthTex, heightTex float32) {
+/*
+ModifyTexRect ... set the texture rectangle at index, flags replace the texture flags of the rectangle.
+Without flags the flags the rectangle was added or last modified with are kept, pass 0 to remove them.
+*/
+func (obj *RenderObject) ModifyTexRect(index int, xTex, yTex, widthTex, heightTex float32, flags ...TexFlag) {
+	obj.checkShapes()
+
 	texs := []float32{
 		// Upper right triangle
 		xTex, yTex,
@@ -246,11 +437,34 @@ func (obj *RenderObject) ModifyTexRect(index int, xTex, yTex, widthTex, heightTe
 		xTex, yTex + heightTex,
 	}
 
-	texs = obj.texture.PixToTex(texs)
+	if len(flags) > 0 {
+		obj.setTexFlags(index, combineTexFlags(flags))
+	}
+
+	texs = obj.texture.PixToTex(applyTexFlags(texs, obj.texFlags[index]))
 
 	obj.vao.UpdateTexBufferIndex(index, texs)
 }
 
+func (obj *RenderObject) setTexFlags(index int, flags TexFlag) {
+	if flags == 0 {
+		delete(obj.texFlags, index)
+
+		return
+	}
+
+	if obj.texFlags == nil {
+		obj.texFlags = make(map[int]TexFlag)
+	}
+
+	obj.texFlags[index] = flags
+}
+
+// TexFlags ... texture flags of the rectangle at index
+func (obj *RenderObject) TexFlags(index int) TexFlag {
+	return obj.texFlags[index]
+}
+
 func (obj *RenderObject) ModifySquare(index int, x, y, xTex, yTex, width, widthTex float32) {
 	obj.ModifyVertSquare(index, x, y, width)
 	obj.ModifyTexSquare(index, xTex, yTex, widthTex)
@@ -258,7 +472,7 @@ func (obj *RenderObject) ModifySquare(index int, x, y, xTex, yTex, width, widthT
 
 func (obj *RenderObject) ModifyRect(index int, x, y, xTex, yTex, width, height, widthTex, heightTex float32) {
 	obj.ModifyVertRect(index, x, y, width, height)
-	obj.ModifyTexRect(index, x, y, widthTex, heightTex)
+	obj.ModifyTexRect(index, xTex, yTex, widthTex, heightTex)
 }
 
 // Clear a square, does not delete the object.
@@ -266,10 +480,123 @@ func (obj *RenderObject) ClearSquare(index int) {
 	obj.ModifyVertSquare(index, 0, 0, 0)
 }
 
+// Clear ... clear every shape in the render object and allow the buffer to be reused from the start
+func (obj *RenderObject) Clear() {
+	obj.checkShapes()
+
+	for index := range obj.animations {
+		obj.StopAnimation(index)
+	}
+
+	obj.ResetGroupedRotation()
+	obj.SetColour(0, obj.freeVert, mgl32.Vec4{1, 1, 1, 1})
+	obj.vao.UpdateVertBufferIndex(0, make([]float32, obj.freeVert*opengl.DEFAULT_VECTOR_SIZE))
+	obj.freeVert = 0
+	obj.nineSlices = nil
+	obj.hidden = nil
+	obj.texFlags = nil
+}
+
+// SetColour ... set the colour of count vertices from index, the colour is multiplied with the texture
+func (obj *RenderObject) SetColour(index, count int, colour mgl32.Vec4) {
+	obj.checkShapes()
+
+	colours := make([]float32, count*opengl.DEFAULT_COLOUR_SIZE)
+
+	for i := 0; i < count; i++ {
+		copy(colours[i*opengl.DEFAULT_COLOUR_SIZE:], colour[:])
+	}
+
+	obj.vao.UpdateColourBufferIndex(index, colours)
+}
+
+// Rotate ... rotate every vertex by rad radians about x, y in pixels
 func (obj *RenderObject) Rotate(x, y, rad float32) {
-	nX, nY := NormVert(x, y)
+	obj.vao.SetRotation(x, y, rad)
+}
+
+// SetScreenSpace ... screen space render objects are not moved by the camera, eg for HUDs
+func (obj *RenderObject) SetScreenSpace(screenSpace bool) {
+	obj.screenSpace = screenSpace
+}
+
+func (obj *RenderObject) ScreenSpace() bool {
+	return obj.screenSpace
+}
+
+/*
+Visibility
+*/
 
-	obj.vao.SetRotation(nX, nY, rad)
+// SetVisible ... invisible render objects are not drawn or picked, their shapes are left unchanged
+func (obj *RenderObject) SetVisible(visible bool) {
+	obj.invisible = !visible
+}
+
+func (obj *RenderObject) Visible() bool {
+	return !obj.invisible
+}
+
+/*
+Hide ... hide the shape at index, its vertices are kept so it can be shown again.
+Modifying the vertices of a hidden shape will show it, Show then restores the vertices from when it was hidden.
+*/
+func (obj *RenderObject) Hide(index int) {
+	obj.checkShapes()
+
+	if obj.Hidden(index) {
+		return
+	}
+
+	if obj.hidden == nil {
+		obj.hidden = make(map[int][]float32)
+	}
+
+	verts := make([]float32, 6*opengl.DEFAULT_VECTOR_SIZE)
+	for i := 0; i < 6; i++ {
+		verts[i*2], verts[i*2+1] = obj.vao.Vert(index + i)
+	}
+
+	obj.hidden[index] = verts
+	obj.vao.UpdateVertBufferIndex(index, make([]float32, 6*opengl.DEFAULT_VECTOR_SIZE))
+}
+
+// Show ... restore a shape hidden with Hide
+func (obj *RenderObject) Show(index int) {
+	obj.checkShapes()
+
+	verts, hidden := obj.hidden[index]
+	if !hidden {
+		return
+	}
+
+	delete(obj.hidden, index)
+	obj.vao.UpdateVertBufferIndex(index, verts)
+}
+
+func (obj *RenderObject) Hidden(index int) bool {
+	_, hidden := obj.hidden[index]
+
+	return hidden
+}
+
+/*
+Layers, render objects are drawn in layer order then in creation order
+*/
+
+func (obj *RenderObject) SetLayer(layer int) {
+	obj.layer = layer
+	sortRenderObjects()
+}
+
+func (obj *RenderObject) Layer() int {
+	return obj.layer
+}
+
+func sortRenderObjects() {
+	sort.SliceStable(renderObjects, func(i, j int) bool {
+		return renderObjects[i].layer < renderObjects[j].layer
+	})
 }
 
 /*
@@ -277,15 +604,25 @@ Rotation group methods
 */
 
 func (obj *RenderObject) ResetGroupedRotation() {
+	obj.checkShapes()
+
 	obj.vao.ResetGroupedRotation()
+	obj.vao.UpdateRotGroupBufferIndex(0, obj.freeVert)
 }
 
 func (obj *RenderObject) SetAllGroupedRotation(x, y, rad float32) {
+	obj.checkShapes()
+
 	obj.vao.SetAllGroupedRotation(x, y, rad)
+	obj.vao.UpdateRotGroupBufferIndex(0, obj.freeVert)
 }
 
+// SetGroupedRotation ... rotate the vertices from start up to end by rad radians about x, y
 func (obj *RenderObject) SetGroupedRotation(x, y, rad float32, start, end int) {
+	obj.checkShapes()
+
 	obj.vao.SetGroupedRotation(x, y, rad, start, end)
+	obj.vao.UpdateRotGroupBufferIndex(start, end)
 }
 
 /*
@@ -295,9 +632,6 @@ Pointer methods, variables that change but need checking each render cycle
 const (
 	transXPtr = iota
 	transYPtr = iota
-	camXPtr   = iota
-	camYPtr   = iota
-	zoomPtr   = iota
 	ptrNum    = iota
 )
 
@@ -306,46 +640,58 @@ func (obj *RenderObject) SetTranslate(x, y *float32) {
 	obj.ptrVars[transYPtr] = y
 }
 
+// SetCamera ... move the current camera to x, y in pixels, the values are read once when called
+//
+// Deprecated: the camera is shared by every render object, use CurrentCamera().SetPosition.
 func (obj *RenderObject) SetCamera(x, y *float32) {
-	obj.ptrVars[camXPtr] = x
-	obj.ptrVars[camYPtr] = y
+	camera.SetPosition(*x, *y)
 }
 
+// SetZoom ... set the zoom of the current camera, the value is read once when called
+//
+// Deprecated: the camera is shared by every render object, use CurrentCamera().SetZoom.
 func (obj *RenderObject) SetZoom(z *float32) {
-	obj.ptrVars[zoomPtr] = z
+	camera.SetZoom(*z)
 }
 
 func (obj *RenderObject) InitPointers() {
 	var (
 		x float32 = 0
 		y float32 = 0
-		z float32 = 1
 	)
 
 	obj.ptrVars = make([]*float32, ptrNum)
 
 	obj.SetTranslate(&x, &y)
-	obj.SetCamera(&x, &y)
-	obj.SetZoom(&z)
 }
 
 func (obj *RenderObject) PrepPointers() {
-	// Set camera
-	nX, nY := NormVert(*obj.ptrVars[camXPtr], *obj.ptrVars[camYPtr])
-	obj.vao.SetCamera(nX, nY)
+	// Set the size of the view being drawn to
+	obj.vao.SetDimensions(drawView.width, drawView.height)
+
+	// Set camera, screen space objects use an unmoved camera
+	if obj.screenSpace {
+		obj.vao.SetCamera(0, 0, 0)
+		obj.vao.SetZoom(1)
+	} else {
+		c := drawView.camera
+		obj.vao.SetCamera(c.x, c.y, c.rot)
+		obj.vao.SetZoom(c.zoom)
+	}
 
 	// Set Translation
-	nX, nY = NormVert(*obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr])
-	obj.vao.SetTranslation(nX, nY-2)
-
-	// Set zoom
-	obj.vao.SetZoom(*obj.ptrVars[zoomPtr])
+	obj.vao.SetTranslation(*obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr])
 }
 
 /*
 Utility methods
 */
 
+// rect ... rectangle in pixels from its top left
+type rect struct {
+	x, y, width, height float32
+}
+
 func NormVert(x, y float32) (nX, nY float32) {
 	nX = x / (windowWidth / 2)
 	nY = y / (windowHeight / 2)
@@ -4,6 +4,8 @@ import (
 	"gopengl/graphics/opengl"
 	"time"
 	"unsafe"
+
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 /*
@@ -176,6 +178,7 @@ func callAddRect(job RenderObjectJob) {
 		params[5].(float32),
 		params[6].(float32),
 		params[7].(float32),
+		params[8].([]TexFlag)...,
 	)
 
 	*(*int)(job.retVal) = freeVert
@@ -185,11 +188,12 @@ func callModifyTexRect(job RenderObjectJob) {
 	params := job.params
 
 	job.obj.ModifyTexRect(
-		params[0].(int),
+		*params[0].(*int),
 		params[1].(float32),
 		params[2].(float32),
 		params[3].(float32),
 		params[4].(float32),
+		params[5].([]TexFlag)...,
 	)
 }
 
@@ -231,6 +235,59 @@ func callSetGroupedRotation(job RenderObjectJob) {
 	)
 }
 
+func callSetColour(job RenderObjectJob) {
+	params := job.params
+
+	job.obj.SetColour(
+		*params[0].(*int),
+		params[1].(int),
+		params[2].(mgl32.Vec4),
+	)
+}
+
+func callSetScreenSpace(job RenderObjectJob) {
+	job.obj.SetScreenSpace(job.params[0].(bool))
+}
+
+func callSetLayer(job RenderObjectJob) {
+	job.obj.SetLayer(job.params[0].(int))
+}
+
+func callSetCulling(job RenderObjectJob) {
+	job.obj.SetCulling(job.params[0].(bool))
+}
+
+func callSetVisible(job RenderObjectJob) {
+	job.obj.SetVisible(job.params[0].(bool))
+}
+
+func callHide(job RenderObjectJob) {
+	job.obj.Hide(*job.params[0].(*int))
+}
+
+func callShow(job RenderObjectJob) {
+	job.obj.Show(*job.params[0].(*int))
+}
+
+func callClear(job RenderObjectJob) {
+	job.obj.Clear()
+}
+
+func callSetClearColor(job RenderObjectJob) {
+	params := job.params
+
+	SetClearColor(
+		params[0].(float32),
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+	)
+}
+
+func callSetClearFlags(job RenderObjectJob) {
+	SetClearFlags(job.params[0].(ClearFlag))
+}
+
 func callUpdateBuffers(job RenderObjectJob) {
 	job.obj.vao.UpdateBuffers()
 }
@@ -316,12 +373,12 @@ func (obj *RenderObject) SetGroupedRotationJob(x, y, rad float32, start, end int
 	}
 }
 
-func (obj *RenderObject) AddRectJob(x, y, xTex, yTex, width, height, widthTex, heightTex float32) *int {
+func (obj *RenderObject) AddRectJob(x, y, xTex, yTex, width, height, widthTex, heightTex float32, flags ...TexFlag) *int {
 	freeVert := 0
 
 	RenderObjectQueue <- RenderObjectJob{
 		obj,
-		[]interface{}{x, y, xTex, yTex, width, height, widthTex, heightTex},
+		[]interface{}{x, y, xTex, yTex, width, height, widthTex, heightTex, flags},
 		unsafe.Pointer(&freeVert),
 		callAddRect,
 	}
@@ -329,7 +386,94 @@ func (obj *RenderObject) AddRectJob(x, y, xTex, yTex, width, height, widthTex, h
 	return &freeVert
 }
 
+// ModifyTexRectJob ... index is read when the job is performed
+func (obj *RenderObject) ModifyTexRectJob(index *int, xTex, yTex, widthTex, heightTex float32, flags ...TexFlag) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index, xTex, yTex, widthTex, heightTex, flags},
+		nil,
+		callModifyTexRect,
+	}
+}
+
+// SetColourJob ... index is read when the job is performed
+func (obj *RenderObject) SetColourJob(index *int, count int, colour mgl32.Vec4) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index, count, colour},
+		nil,
+		callSetColour,
+	}
+}
+
+func (obj *RenderObject) SetScreenSpaceJob(screenSpace bool) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{screenSpace},
+		nil,
+		callSetScreenSpace,
+	}
+}
+
+func (obj *RenderObject) SetLayerJob(layer int) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{layer},
+		nil,
+		callSetLayer,
+	}
+}
+
+func (obj *RenderObject) SetCullingJob(enabled bool) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{enabled},
+		nil,
+		callSetCulling,
+	}
+}
+
+func (obj *RenderObject) SetVisibleJob(visible bool) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{visible},
+		nil,
+		callSetVisible,
+	}
+}
+
+// HideJob ... index is read when the job is performed
+func (obj *RenderObject) HideJob(index *int) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index},
+		nil,
+		callHide,
+	}
+}
+
+// ShowJob ... index is read when the job is performed
+func (obj *RenderObject) ShowJob(index *int) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index},
+		nil,
+		callShow,
+	}
+}
+
+func (obj *RenderObject) ClearJob() {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		nil,
+		nil,
+		callClear,
+	}
+}
+
 func (obj *RenderObject) UpdateBuffersJob() {
+	obj.checkShapes()
+
 	RenderObjectQueue <- RenderObjectJob{
 		obj,
 		nil,
@@ -338,6 +482,24 @@ func (obj *RenderObject) UpdateBuffersJob() {
 	}
 }
 
+func SetClearColorJob(r, g, b, a float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{r, g, b, a},
+		nil,
+		callSetClearColor,
+	}
+}
+
+func SetClearFlagsJob(flags ClearFlag) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{flags},
+		nil,
+		callSetClearFlags,
+	}
+}
+
 /*
 Cleanup
 */
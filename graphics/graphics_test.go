@@ -0,0 +1,33 @@
+package graphics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyTexFlags(t *testing.T) {
+	tl, tr, br, bl := [2]float32{0, 0}, [2]float32{1, 0}, [2]float32{1, 1}, [2]float32{0, 1}
+
+	tests := []struct {
+		name  string
+		flags []TexFlag
+		want  []float32
+	}{
+		{"none", nil, rectTexs(tl, tr, br, bl)},
+		{"flip x", []TexFlag{FlipX}, rectTexs(tr, tl, bl, br)},
+		{"flip y", []TexFlag{FlipY}, rectTexs(bl, br, tr, tl)},
+		{"flip x and y", []TexFlag{FlipX, FlipY}, rectTexs(br, bl, tl, tr)},
+		{"combined flag", []TexFlag{FlipX | FlipY}, rectTexs(br, bl, tl, tr)},
+		{"rotate 90", []TexFlag{Rotate90}, rectTexs(tr, br, bl, tl)},
+		{"rotate 90 and flip x", []TexFlag{Rotate90, FlipX}, rectTexs(br, tr, tl, bl)},
+		{"rotate 90 and flip y", []TexFlag{Rotate90, FlipY}, rectTexs(tl, bl, br, tr)},
+	}
+
+	for _, test := range tests {
+		got := applyTexFlags(rectTexs(tl, tr, br, bl), combineTexFlags(test.flags))
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
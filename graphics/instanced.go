@@ -0,0 +1,289 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+	"unsafe"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Instanced render objects draw a single quad many times in one draw call, eg for thousands of bullets.
+Each instance only stores its position, rotation, scale, texture rectangle and colour, the quads vertices are shared.
+Instanced render objects are drawn, layered, translated and rotated like any other render object but are not culled
+or picked, shapes cannot be added to or edited on them.
+*/
+
+// Instance ... a single quad of an instanced render object
+type Instance struct {
+	// X, Y ... position of the centre of the quad in pixels
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+	// Rotation ... clockwise rotation in radians about the centre of the quad
+	Rotation float32 `json:"rotation"`
+	ScaleX   float32 `json:"scaleX"`
+	ScaleY   float32 `json:"scaleY"`
+	// TexRect ... texture of the quad, the whole texture is used if it has no width
+	TexRect TexRect    `json:"texRect"`
+	Colour  mgl32.Vec4 `json:"colour"`
+}
+
+type instancing struct {
+	vao   *opengl.InstancedVAO
+	count int
+}
+
+// CreateInstance ... an unrotated white instance at x, y with a scale of 1
+func CreateInstance(x, y float32) Instance {
+	return Instance{
+		X:      x,
+		Y:      y,
+		ScaleX: 1,
+		ScaleY: 1,
+		Colour: mgl32.Vec4{1, 1, 1, 1},
+	}
+}
+
+// CreateInstancedRenderObject ... capacity is the maximum number of instances, width and height are the size of the quad in pixels
+func CreateInstancedRenderObject(obj *RenderObject, capacity int, texture string, width, height float32) {
+	vao := opengl.CreateInstancedVAO(uint32(capacity), texture, width, height, windowWidth, windowHeight)
+
+	obj.vao = &vao.VAO
+	obj.texture = vao.Texture
+	obj.instanced = &instancing{vao: vao}
+	obj.InitPointers()
+	obj.SetCulling(false)
+
+	renderObjects = append(renderObjects, obj)
+	sortRenderObjects()
+}
+
+// Instanced ... if the render object was created with CreateInstancedRenderObject
+func (obj *RenderObject) Instanced() bool {
+	return obj.instanced != nil
+}
+
+// AddInstance ... returns the index of the new instance
+func (obj *RenderObject) AddInstance(inst Instance) int {
+	in := obj.instancing()
+
+	if in.count >= in.vao.Capacity() {
+		panic("Render Object Instance overflow")
+	}
+
+	obj.ModifyInstance(in.count, inst)
+	in.count++
+
+	return in.count - 1
+}
+
+func (obj *RenderObject) ModifyInstance(index int, inst Instance) {
+	obj.instancing().vao.UpdateInstanceIndex(index, obj.instanceData(inst))
+}
+
+// SetInstances ... replace every instance with instances in a single upload, eg when they all move each frame
+func (obj *RenderObject) SetInstances(instances []Instance) {
+	in := obj.instancing()
+
+	if len(instances) > in.vao.Capacity() {
+		panic("Render Object Instance overflow")
+	}
+
+	data := make([]float32, 0, len(instances)*opengl.INSTANCE_SIZE)
+	for _, inst := range instances {
+		data = append(data, obj.instanceData(inst)...)
+	}
+
+	in.vao.UpdateInstanceIndex(0, data)
+	in.count = len(instances)
+}
+
+/*
+RemoveInstance ... remove the instance at index by swapping the last instance into index so the instances stay packed,
+the index of the last instance becomes index.
+*/
+func (obj *RenderObject) RemoveInstance(index int) {
+	in := obj.instancing()
+
+	if index < 0 || index >= in.count {
+		panic("Render Object Instance index out of range")
+	}
+
+	in.count--
+
+	if index != in.count {
+		in.vao.UpdateInstanceIndex(index, in.vao.Instance(in.count))
+	}
+}
+
+// ClearInstances ... remove every instance, the instance buffer is reused from the start
+func (obj *RenderObject) ClearInstances() {
+	obj.instancing().count = 0
+}
+
+func (obj *RenderObject) InstanceCount() int {
+	return obj.instancing().count
+}
+
+// SetInstanceSize ... size of the quad in pixels before each instance is scaled
+func (obj *RenderObject) SetInstanceSize(width, height float32) {
+	obj.instancing().vao.SetSize(width, height)
+}
+
+func (obj *RenderObject) instancing() *instancing {
+	if obj.instanced == nil {
+		panic("Render object is not instanced")
+	}
+
+	return obj.instanced
+}
+
+// checkShapes ... panic if the render object is instanced, instanced VAOs have no per vertex buffers to edit
+func (obj *RenderObject) checkShapes() {
+	if obj.instanced != nil {
+		panic("Shapes cannot be edited on instanced render objects, use ModifyInstance")
+	}
+}
+
+func (obj *RenderObject) instanceData(inst Instance) []float32 {
+	texWidth := float32(obj.texture.Width())
+	texHeight := float32(obj.texture.Height())
+
+	texRect := inst.TexRect
+	if texRect.Width == 0 {
+		texRect = TexRect{0, 0, texWidth, texHeight}
+	}
+
+	return []float32{
+		inst.X, inst.Y,
+		inst.Rotation,
+		inst.ScaleX, inst.ScaleY,
+		texRect.X / texWidth, texRect.Y / texHeight, texRect.Width / texWidth, texRect.Height / texHeight,
+		inst.Colour[0], inst.Colour[1], inst.Colour[2], inst.Colour[3],
+	}
+}
+
+// instance ... the instance at index as it was added
+func (obj *RenderObject) instance(index int) Instance {
+	data := obj.instanced.vao.Instance(index)
+	texWidth := float32(obj.texture.Width())
+	texHeight := float32(obj.texture.Height())
+
+	return Instance{
+		X:        data[0],
+		Y:        data[1],
+		Rotation: data[2],
+		ScaleX:   data[3],
+		ScaleY:   data[4],
+		TexRect:  TexRect{data[5] * texWidth, data[6] * texHeight, data[7] * texWidth, data[8] * texHeight},
+		Colour:   mgl32.Vec4{data[9], data[10], data[11], data[12]},
+	}
+}
+
+// renderInstances ... draw every instance in a single draw call
+func (obj *RenderObject) renderInstances() {
+	if obj.instanced.count == 0 {
+		return
+	}
+
+	obj.PrepRender()
+	obj.instanced.vao.DrawInstances(obj.instanced.count)
+	obj.FinishRender()
+}
+
+/*
+Instance jobs
+*/
+
+func callCreateInstancedRenderObject(job RenderObjectJob) {
+	params := job.params
+
+	CreateInstancedRenderObject(
+		job.obj,
+		params[0].(int),
+		params[1].(string),
+		params[2].(float32),
+		params[3].(float32),
+	)
+}
+
+func callAddInstance(job RenderObjectJob) {
+	*(*int)(job.retVal) = job.obj.AddInstance(job.params[0].(Instance))
+}
+
+func callModifyInstance(job RenderObjectJob) {
+	params := job.params
+
+	job.obj.ModifyInstance(*params[0].(*int), params[1].(Instance))
+}
+
+func callSetInstances(job RenderObjectJob) {
+	job.obj.SetInstances(job.params[0].([]Instance))
+}
+
+func callRemoveInstance(job RenderObjectJob) {
+	job.obj.RemoveInstance(*job.params[0].(*int))
+}
+
+func callClearInstances(job RenderObjectJob) {
+	job.obj.ClearInstances()
+}
+
+func CreateInstancedRenderObjectJob(obj *RenderObject, capacity int, texture string, width, height float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{capacity, texture, width, height},
+		nil,
+		callCreateInstancedRenderObject,
+	}
+}
+
+func (obj *RenderObject) AddInstanceJob(inst Instance) *int {
+	index := 0
+
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{inst},
+		unsafe.Pointer(&index),
+		callAddInstance,
+	}
+
+	return &index
+}
+
+func (obj *RenderObject) ModifyInstanceJob(index *int, inst Instance) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index, inst},
+		nil,
+		callModifyInstance,
+	}
+}
+
+// SetInstancesJob ... instances must not be modified until the job has been performed
+func (obj *RenderObject) SetInstancesJob(instances []Instance) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{instances},
+		nil,
+		callSetInstances,
+	}
+}
+
+func (obj *RenderObject) RemoveInstanceJob(index *int) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index},
+		nil,
+		callRemoveInstance,
+	}
+}
+
+func (obj *RenderObject) ClearInstancesJob() {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		nil,
+		nil,
+		callClearInstances,
+	}
+}
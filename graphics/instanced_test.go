@@ -0,0 +1,36 @@
+package graphics
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestInstancedShapeEditsPanic(t *testing.T) {
+	obj := &RenderObject{instanced: &instancing{}}
+
+	tests := []struct {
+		name string
+		edit func()
+	}{
+		{"AddSquare", func() { obj.AddSquare(0, 0, 0, 0, 1, 1) }},
+		{"AddRect", func() { obj.AddRect(0, 0, 0, 0, 1, 1, 1, 1) }},
+		{"ModifyVertRect", func() { obj.ModifyVertRect(0, 0, 0, 1, 1) }},
+		{"ModifyTexRect", func() { obj.ModifyTexRect(0, 0, 0, 1, 1) }},
+		{"ModifyRect", func() { obj.ModifyRect(0, 0, 0, 0, 0, 1, 1, 1, 1) }},
+		{"Clear", func() { obj.Clear() }},
+		{"SetColour", func() { obj.SetColour(0, 6, mgl32.Vec4{1, 0, 0, 1}) }},
+		{"Hide", func() { obj.Hide(0) }},
+		{"Show", func() { obj.Show(0) }},
+		{"ResetGroupedRotation", func() { obj.ResetGroupedRotation() }},
+		{"SetAllGroupedRotation", func() { obj.SetAllGroupedRotation(0, 0, 1) }},
+		{"SetGroupedRotation", func() { obj.SetGroupedRotation(0, 0, 1, 0, 6) }},
+		{"UpdateBuffersJob", func() { obj.UpdateBuffersJob() }},
+	}
+
+	for _, test := range tests {
+		if !panics(test.edit) {
+			t.Errorf("%s: did not panic", test.name)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package graphics
+
+import (
+	"unsafe"
+)
+
+/*
+Nine slice rectangles, the corners are drawn unscaled, the edges are stretched along their length and the centre is
+stretched to fill the remaining space. Used for UI panels whose borders should not stretch.
+*/
+
+// Insets ... width of each border in pixels, the borders are drawn at the same size they are in the texture
+type Insets struct {
+	Left, Top, Right, Bottom float32
+}
+
+type nineSlice struct {
+	x, y, width, height float32
+	texRect             TexRect
+	insets              Insets
+}
+
+/*
+AddNineSlice ... add a width x height nine slice with its top left at x, y, texRect is the whole panel in the texture.
+Returns the index of the first vertex, the nine rectangles use 54 vertices.
+*/
+func (obj *RenderObject) AddNineSlice(x, y, width, height float32, texRect TexRect, insets Insets) int {
+	// Checked before any rectangle is added so an overflow doesn't leave part of the nine slice behind
+	if obj.freeVert+54 > obj.maxVert {
+		panic("Render Object Buffer overflow")
+	}
+
+	slice := nineSlice{x, y, width, height, texRect, insets}
+	verts := slice.verts()
+	texs := slice.texs()
+	start := obj.freeVert
+
+	for i := 0; i < 9; i++ {
+		obj.AddRect(
+			verts[i].x,
+			verts[i].y,
+			texs[i].x,
+			texs[i].y,
+			verts[i].width,
+			verts[i].height,
+			texs[i].width,
+			texs[i].height,
+		)
+	}
+
+	if obj.nineSlices == nil {
+		obj.nineSlices = make(map[int]nineSlice)
+	}
+
+	obj.nineSlices[start] = slice
+
+	return start
+}
+
+// ModifyNineSlice ... move or resize the nine slice at index, only rectangles that have changed are updated
+func (obj *RenderObject) ModifyNineSlice(index int, x, y, width, height float32) {
+	slice, exists := obj.nineSlices[index]
+	if !exists {
+		panic("No nine slice at index")
+	}
+
+	oldVerts := slice.verts()
+	slice.x, slice.y, slice.width, slice.height = x, y, width, height
+	verts := slice.verts()
+
+	for i := 0; i < 9; i++ {
+		if verts[i] != oldVerts[i] {
+			obj.ModifyVertRect(index+i*6, verts[i].x, verts[i].y, verts[i].width, verts[i].height)
+		}
+	}
+
+	obj.nineSlices[index] = slice
+}
+
+// verts ... rectangles of the nine slice row by row from the top left
+func (slice nineSlice) verts() [9]rect {
+	insets := slice.insets
+
+	// Shrink the borders if they don't fit
+	if horizontal := insets.Left + insets.Right; horizontal > slice.width && horizontal > 0 {
+		insets.Left *= slice.width / horizontal
+		insets.Right *= slice.width / horizontal
+	}
+
+	if vertical := insets.Top + insets.Bottom; vertical > slice.height && vertical > 0 {
+		insets.Top *= slice.height / vertical
+		insets.Bottom *= slice.height / vertical
+	}
+
+	return sliceGrid(
+		[4]float32{slice.x, slice.x + insets.Left, slice.x + slice.width - insets.Right, slice.x + slice.width},
+		[4]float32{slice.y, slice.y + insets.Top, slice.y + slice.height - insets.Bottom, slice.y + slice.height},
+	)
+}
+
+// texs ... texture rectangles of the nine slice row by row from the top left
+func (slice nineSlice) texs() [9]rect {
+	texRect := slice.texRect
+	insets := slice.insets
+
+	return sliceGrid(
+		[4]float32{texRect.X, texRect.X + insets.Left, texRect.X + texRect.Width - insets.Right, texRect.X + texRect.Width},
+		[4]float32{texRect.Y, texRect.Y + insets.Top, texRect.Y + texRect.Height - insets.Bottom, texRect.Y + texRect.Height},
+	)
+}
+
+func sliceGrid(xs, ys [4]float32) [9]rect {
+	var rects [9]rect
+
+	for row := 0; row < 3; row++ {
+		for column := 0; column < 3; column++ {
+			rects[row*3+column] = rect{
+				xs[column],
+				ys[row],
+				xs[column+1] - xs[column],
+				ys[row+1] - ys[row],
+			}
+		}
+	}
+
+	return rects
+}
+
+/*
+Nine slice jobs
+*/
+
+func callAddNineSlice(job RenderObjectJob) {
+	params := job.params
+
+	index := job.obj.AddNineSlice(
+		params[0].(float32),
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+		params[4].(TexRect),
+		params[5].(Insets),
+	)
+
+	*(*int)(job.retVal) = index
+}
+
+func callModifyNineSlice(job RenderObjectJob) {
+	params := job.params
+
+	job.obj.ModifyNineSlice(
+		*params[0].(*int),
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+		params[4].(float32),
+	)
+}
+
+func (obj *RenderObject) AddNineSliceJob(x, y, width, height float32, texRect TexRect, insets Insets) *int {
+	index := 0
+
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{x, y, width, height, texRect, insets},
+		unsafe.Pointer(&index),
+		callAddNineSlice,
+	}
+
+	return &index
+}
+
+// ModifyNineSliceJob ... index is read when the job is performed
+func (obj *RenderObject) ModifyNineSliceJob(index *int, x, y, width, height float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index, x, y, width, height},
+		nil,
+		callModifyNineSlice,
+	}
+}
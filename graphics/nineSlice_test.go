@@ -0,0 +1,77 @@
+package graphics
+
+import (
+	"testing"
+)
+
+func TestSliceGrid(t *testing.T) {
+	got := sliceGrid([4]float32{0, 10, 90, 100}, [4]float32{0, 5, 45, 50})
+	want := [9]rect{
+		{0, 0, 10, 5}, {10, 0, 80, 5}, {90, 0, 10, 5},
+		{0, 5, 10, 40}, {10, 5, 80, 40}, {90, 5, 10, 40},
+		{0, 45, 10, 5}, {10, 45, 80, 5}, {90, 45, 10, 5},
+	}
+
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestNineSliceVerts(t *testing.T) {
+	tests := []struct {
+		name                string
+		x, y, width, height float32
+		insets              Insets
+		want                [9]rect
+	}{
+		{"stretched", 10, 20, 100, 60, Insets{8, 4, 16, 12}, [9]rect{
+			{10, 20, 8, 4}, {18, 20, 76, 4}, {94, 20, 16, 4},
+			{10, 24, 8, 44}, {18, 24, 76, 44}, {94, 24, 16, 44},
+			{10, 68, 8, 12}, {18, 68, 76, 12}, {94, 68, 16, 12},
+		}},
+		{"borders shrunk to fit", 0, 0, 12, 8, Insets{8, 8, 16, 8}, [9]rect{
+			{0, 0, 4, 4}, {4, 0, 0, 4}, {4, 0, 8, 4},
+			{0, 4, 4, 0}, {4, 4, 0, 0}, {4, 4, 8, 0},
+			{0, 4, 4, 4}, {4, 4, 0, 4}, {4, 4, 8, 4},
+		}},
+		{"no borders", 0, 0, 30, 30, Insets{}, [9]rect{
+			{0, 0, 0, 0}, {0, 0, 30, 0}, {30, 0, 0, 0},
+			{0, 0, 0, 30}, {0, 0, 30, 30}, {30, 0, 0, 30},
+			{0, 30, 0, 0}, {0, 30, 30, 0}, {30, 30, 0, 0},
+		}},
+	}
+
+	for _, test := range tests {
+		slice := nineSlice{test.x, test.y, test.width, test.height, TexRect{}, test.insets}
+
+		if got := slice.verts(); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNineSliceTexs(t *testing.T) {
+	// Texture rectangles keep the borders unscaled even when the drawn borders are shrunk
+	slice := nineSlice{0, 0, 12, 8, TexRect{32, 64, 48, 24}, Insets{8, 4, 16, 12}}
+	want := [9]rect{
+		{32, 64, 8, 4}, {40, 64, 24, 4}, {64, 64, 16, 4},
+		{32, 68, 8, 8}, {40, 68, 24, 8}, {64, 68, 16, 8},
+		{32, 76, 8, 12}, {40, 76, 24, 12}, {64, 76, 16, 12},
+	}
+
+	if got := slice.texs(); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestAddNineSliceOverflow(t *testing.T) {
+	obj := &RenderObject{freeVert: 6, maxVert: 59}
+
+	if !panics(func() { obj.AddNineSlice(0, 0, 10, 10, TexRect{0, 0, 10, 10}, Insets{}) }) {
+		t.Error("did not panic")
+	}
+
+	if obj.freeVert != 6 || obj.nineSlices != nil {
+		t.Errorf("got %d vertices used and nine slices %v, want the render object unchanged", obj.freeVert, obj.nineSlices)
+	}
+}
@@ -0,0 +1,374 @@
+package graphics
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Scene graph nodes, each node has a transform relative to its parent and can optionally draw a rectangle from a render object.
+World transforms are composed down the tree at the start of each render, only nodes which have changed (or whose parents
+have changed) update their vertices.
+
+The transform of a node is performed about its origin: scaled, then rotated, then moved to its position in the parent.
+Geometry is baked into the vertices so the grouped rotation of a nodes rectangle should not be used.
+*/
+
+type Node struct {
+	parent   *Node
+	children []*Node
+
+	x, y             float32
+	rot              float32
+	scaleX, scaleY   float32
+	originX, originY float32
+
+	// Geometry, obj is nil for nodes without any
+	obj           *RenderObject
+	index         int
+	width, height float32
+
+	world mgl32.Mat3
+	dirty bool
+
+	// clip ... clip rectangle in the nodes coordinates, nil if it doesn't clip
+	clip *rect
+}
+
+// rootNodes ... nodes without a parent, updated each render
+var rootNodes []*Node
+
+func CreateNode() *Node {
+	n := &Node{}
+	n.init()
+
+	return n
+}
+
+func (n *Node) init() {
+	n.scaleX = 1
+	n.scaleY = 1
+	n.world = mgl32.Ident3()
+	n.dirty = true
+
+	rootNodes = append(rootNodes, n)
+}
+
+// AddChild ... move child to this node, removing it from its previous parent
+func (n *Node) AddChild(child *Node) {
+	for ancestor := n; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor == child {
+			panic("Node cannot be added to its own descendant")
+		}
+	}
+
+	child.detach()
+	child.parent = n
+	child.dirty = true
+	n.children = append(n.children, child)
+}
+
+// RemoveChild ... the child becomes a root node
+func (n *Node) RemoveChild(child *Node) {
+	if child.parent != n {
+		return
+	}
+
+	child.detach()
+	child.dirty = true
+	rootNodes = append(rootNodes, child)
+}
+
+// Delete ... remove the node and its children from the tree, their geometry is left as it was last drawn
+func (n *Node) Delete() {
+	n.detach()
+}
+
+// detach ... remove the node from its parent or the root nodes
+func (n *Node) detach() {
+	siblings := &rootNodes
+	if n.parent != nil {
+		siblings = &n.parent.children
+	}
+
+	for i, node := range *siblings {
+		if node == n {
+			*siblings = append((*siblings)[:i], (*siblings)[i+1:]...)
+			break
+		}
+	}
+
+	n.parent = nil
+}
+
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// SetGeometry ... draw the rectangle at index in obj (from AddRect etc) as a width x height rectangle from the nodes origin
+func (n *Node) SetGeometry(obj *RenderObject, index int, width, height float32) {
+	n.obj = obj
+	n.index = index
+	n.width = width
+	n.height = height
+	n.dirty = true
+}
+
+// SetPosition ... position of the nodes origin in its parent
+func (n *Node) SetPosition(x, y float32) {
+	n.x = x
+	n.y = y
+	n.dirty = true
+}
+
+// SetRotation ... rotation about the origin in radians
+func (n *Node) SetRotation(rad float32) {
+	n.rot = rad
+	n.dirty = true
+}
+
+func (n *Node) SetScale(x, y float32) {
+	n.scaleX = x
+	n.scaleY = y
+	n.dirty = true
+}
+
+// SetOrigin ... the point the node is positioned, rotated and scaled about, in unscaled pixels from the geometries top left
+func (n *Node) SetOrigin(x, y float32) {
+	n.originX = x
+	n.originY = y
+	n.dirty = true
+}
+
+func (n *Node) Position() (x, y float32) {
+	return n.x, n.y
+}
+
+func (n *Node) Rotation() float32 {
+	return n.rot
+}
+
+func (n *Node) Scale() (x, y float32) {
+	return n.scaleX, n.scaleY
+}
+
+func (n *Node) Origin() (x, y float32) {
+	return n.originX, n.originY
+}
+
+// LocalTransform ... transform from the node to its parent
+func (n *Node) LocalTransform() mgl32.Mat3 {
+	return mgl32.Translate2D(n.x, n.y).
+		Mul3(mgl32.HomogRotate2D(n.rot)).
+		Mul3(mgl32.Scale2D(n.scaleX, n.scaleY)).
+		Mul3(mgl32.Translate2D(-n.originX, -n.originY))
+}
+
+// WorldTransform ... transform from the node to the world, calculated from the current transforms of its ancestors
+func (n *Node) WorldTransform() mgl32.Mat3 {
+	if n.parent == nil {
+		return n.LocalTransform()
+	}
+
+	return n.parent.WorldTransform().Mul3(n.LocalTransform())
+}
+
+// LocalToWorld ... convert a position relative to the nodes geometry to a world position
+func (n *Node) LocalToWorld(x, y float32) (worldX, worldY float32) {
+	world := n.WorldTransform().Mul3x1(mgl32.Vec3{x, y, 1})
+
+	return world.X(), world.Y()
+}
+
+/*
+Node updating, performed at the start of each render
+*/
+
+func updateNodes() {
+	for _, n := range rootNodes {
+		n.update(mgl32.Ident3(), false)
+	}
+}
+
+func (n *Node) update(parent mgl32.Mat3, parentChanged bool) {
+	changed := n.dirty || parentChanged
+
+	if changed {
+		n.world = parent.Mul3(n.LocalTransform())
+		n.dirty = false
+		n.applyGeometry()
+	}
+
+	for _, child := range n.children {
+		child.update(n.world, changed)
+	}
+}
+
+func (n *Node) applyGeometry() {
+	if n.obj == nil {
+		return
+	}
+
+	corners := [4]mgl32.Vec3{
+		n.world.Mul3x1(mgl32.Vec3{0, 0, 1}),
+		n.world.Mul3x1(mgl32.Vec3{n.width, 0, 1}),
+		n.world.Mul3x1(mgl32.Vec3{n.width, n.height, 1}),
+		n.world.Mul3x1(mgl32.Vec3{0, n.height, 1}),
+	}
+
+	verts := make([]float32, 0, 12)
+
+	// Corners in the same order as AddRect
+	for _, corner := range []int{0, 1, 2, 0, 2, 3} {
+		verts = append(verts, corners[corner].X(), corners[corner].Y())
+	}
+
+	n.obj.vao.UpdateVertBufferIndex(n.index, verts)
+}
+
+/*
+Node jobs, the node is passed as the first parameter.
+*/
+
+func callCreateNode(job RenderObjectJob) {
+	(*Node)(job.retVal).init()
+}
+
+func callNodeAddChild(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Node).AddChild(params[1].(*Node))
+}
+
+func callNodeRemoveChild(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Node).RemoveChild(params[1].(*Node))
+}
+
+func callNodeSetGeometry(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Node).SetGeometry(
+		params[1].(*RenderObject),
+		*params[2].(*int),
+		params[3].(float32),
+		params[4].(float32),
+	)
+}
+
+func callNodeSetPosition(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Node).SetPosition(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+func callNodeSetRotation(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Node).SetRotation(params[1].(float32))
+}
+
+func callNodeSetScale(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Node).SetScale(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+func callNodeSetOrigin(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Node).SetOrigin(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+// CreateNodeJob ... the returned node is populated once the job has been performed, its Job methods can be used immediately
+func CreateNodeJob() *Node {
+	n := &Node{}
+
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		nil,
+		unsafe.Pointer(n),
+		callCreateNode,
+	}
+
+	return n
+}
+
+func (n *Node) AddChildJob(child *Node) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{n, child},
+		nil,
+		callNodeAddChild,
+	}
+}
+
+func (n *Node) RemoveChildJob(child *Node) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{n, child},
+		nil,
+		callNodeRemoveChild,
+	}
+}
+
+// SetGeometryJob ... index is read when the job is performed so the result of AddRectJob can be passed directly
+func (n *Node) SetGeometryJob(obj *RenderObject, index *int, width, height float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{n, obj, index, width, height},
+		nil,
+		callNodeSetGeometry,
+	}
+}
+
+func (n *Node) SetPositionJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{n, x, y},
+		nil,
+		callNodeSetPosition,
+	}
+}
+
+func (n *Node) SetRotationJob(rad float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{n, rad},
+		nil,
+		callNodeSetRotation,
+	}
+}
+
+func (n *Node) SetScaleJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{n, x, y},
+		nil,
+		callNodeSetScale,
+	}
+}
+
+func (n *Node) SetOriginJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{n, x, y},
+		nil,
+		callNodeSetOrigin,
+	}
+}
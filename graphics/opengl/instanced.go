@@ -0,0 +1,184 @@
+package opengl
+
+/*
+Instanced VAO implementation, a single quad is drawn once per instance with glDrawArraysInstanced.
+The position, rotation, scale, texture rectangle and colour of each instance are read from an instance buffer,
+so moving an instance uploads INSTANCE_SIZE floats instead of the vertices of a whole quad.
+*/
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// INSTANCE_SIZE ... floats per instance: x, y, rotation, scale x, scale y, texture rect (u, v, width, height), colour (rgba)
+const INSTANCE_SIZE = 13
+
+// quadVerts ... unit quad in the same vertex order as a rectangle added to a VAO
+var quadVerts = []float32{
+	0, 0,
+	1, 0,
+	1, 1,
+
+	0, 0,
+	1, 1,
+	0, 1,
+}
+
+type InstancedVAO struct {
+	VAO
+	instanceID  uint32
+	instances   []float32
+	instanceNum int32
+	size        mgl32.Vec2 // Size of the quad in pixels before each instance is scaled
+}
+
+// CreateInstancedVAO ... capacity in instances, width and height are the size of the quad in pixels.
+func CreateInstancedVAO(capacity uint32, textureSource string, width, height, windowWidth, windowHeight float32) *InstancedVAO {
+	var vaoID, vertID, instanceID uint32
+
+	gl.GenVertexArrays(1, &vaoID)
+	gl.GenBuffers(1, &vertID)
+	gl.GenBuffers(1, &instanceID)
+
+	// texs, colours and rotGroups are left nil, each instance has its own texture rectangle, colour and rotation
+	vao := &InstancedVAO{
+		VAO: VAO{
+			ID:           vaoID,
+			vertID:       vertID,
+			windowWidth:  windowWidth,
+			windowHeight: windowHeight,
+			verts:        append([]float32(nil), quadVerts...),
+			vertNum:      int32(len(quadVerts) / DEFAULT_VECTOR_SIZE),
+			Texture:      LoadTexture(textureSource),
+			uniforms:     make(map[string]interface{}),
+			cam:          mgl32.Vec4{0, 0, 1, 0},
+			zoom:         1,
+		},
+		instanceID:  instanceID,
+		instances:   make([]float32, capacity*INSTANCE_SIZE),
+		instanceNum: int32(capacity),
+		size:        mgl32.Vec2{width, height},
+	}
+
+	vao.InstancedShader()
+	vao.CreateBuffers()
+	createdVAOs = append(createdVAOs, &vao.VAO)
+
+	return vao
+}
+
+/*
+VBO creation and setup
+*/
+
+func (vao *InstancedVAO) CreateBuffers() {
+	vao.created = true
+
+	gl.BindVertexArray(vao.ID)
+
+	//quad buffer, shared by every instance
+	gl.BindBuffer(gl.ARRAY_BUFFER, vao.vertID)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(vao.verts), gl.Ptr(vao.verts), gl.STATIC_DRAW)
+	vertAttrib := vao.shader.EnableAttribute("vert")
+	gl.VertexAttribPointer(vertAttrib, DEFAULT_VECTOR_SIZE, gl.FLOAT, false, 0, nil)
+
+	//instance buffer, each attribute advances once per instance
+	gl.BindBuffer(gl.ARRAY_BUFFER, vao.instanceID)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(vao.instances), gl.Ptr(vao.instances), gl.DYNAMIC_DRAW)
+
+	offset := 0
+	for _, attribute := range []struct {
+		name string
+		size int32
+	}{
+		{"instpos", 2},
+		{"instrot", 1},
+		{"instscale", 2},
+		{"insttexrect", 4},
+		{"instcolour", 4},
+	} {
+		attrib := vao.shader.EnableAttribute(attribute.name)
+		gl.VertexAttribPointer(attrib, attribute.size, gl.FLOAT, false, 4*INSTANCE_SIZE, gl.PtrOffset(4*offset))
+		gl.VertexAttribDivisor(attrib, 1)
+
+		offset += int(attribute.size)
+	}
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindVertexArray(0)
+}
+
+// UpdateInstanceIndex ... instanceData is INSTANCE_SIZE floats per instance, index is the first instance to update
+func (vao *InstancedVAO) UpdateInstanceIndex(index int, instanceData []float32) {
+	index *= INSTANCE_SIZE
+	copy(vao.instances[index:], instanceData)
+
+	vao.updateBufferRange(vao.instanceID, index, vao.instances[index:index+len(instanceData)])
+}
+
+// Instance ... data of instance index as set by UpdateInstanceIndex
+func (vao *InstancedVAO) Instance(index int) []float32 {
+	return vao.instances[index*INSTANCE_SIZE : (index+1)*INSTANCE_SIZE]
+}
+
+func (vao *InstancedVAO) Capacity() int {
+	return int(vao.instanceNum)
+}
+
+// SetSize ... size of the quad in pixels before each instance is scaled
+func (vao *InstancedVAO) SetSize(width, height float32) {
+	vao.size = mgl32.Vec2{width, height}
+	vao.shader.SetUniform("size", vao.size)
+}
+
+func (vao *InstancedVAO) Size() (width, height float32) {
+	return vao.size.X(), vao.size.Y()
+}
+
+func (vao *InstancedVAO) Delete() {
+	gl.DeleteBuffers(1, &vao.instanceID)
+	vao.VAO.Delete()
+}
+
+/*
+Render handling, the vao must have been prepared with PrepRender
+*/
+
+// DrawInstances ... draw the first count instances
+func (vao *InstancedVAO) DrawInstances(count int) {
+	gl.DrawArraysInstanced(gl.TRIANGLES, 0, vao.vertNum, int32(count))
+}
+
+/*
+Utility
+*/
+
+func (vao *InstancedVAO) InstancedShader() Program {
+	program := CreateProgram(0)
+	vao.AttachProgram(program)
+
+	program.LoadVertShader("./shaders/instanced.vert")
+	program.LoadFragShader("./shaders/fragment.frag")
+	program.Link()
+
+	program.AddAttribute("vert")
+	program.AddAttribute("instpos")
+	program.AddAttribute("instrot")
+	program.AddAttribute("instscale")
+	program.AddAttribute("insttexrect")
+	program.AddAttribute("instcolour")
+
+	vao.AddUniform("rot", mgl32.Vec4{})
+	vao.SetRotation(0, 0, 0)
+
+	var zoom float32 = 1
+
+	vao.AddUniform("size", vao.size)
+	vao.AddUniform("trans", mgl32.Vec2{})
+	vao.AddUniform("dim", mgl32.Vec2{vao.windowWidth, vao.windowHeight})
+	vao.AddUniform("cam", mgl32.Vec4{0, 0, 1, 0})
+	vao.AddUniform("zoom", zoom)
+
+	return *program
+}
@@ -13,8 +13,7 @@ func GlInit() {
 }
 
 func Render(vaos []*VAO) {
-	gl.ClearColor(0.0, 0.0, 0.0, 1.0)
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	Clear()
 	var vao *VAO
 
 	for i := 0; i < len(vaos); i++ {
@@ -25,3 +24,30 @@ func Render(vaos []*VAO) {
 		vao.FinishRender()
 	}
 }
+
+/*
+Clearing, performed at the start of each render
+*/
+
+var (
+	clearColour        = [4]float32{0, 0, 0, 1}
+	clearMask   uint32 = gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT
+)
+
+func SetClearColor(r, g, b, a float32) {
+	clearColour = [4]float32{r, g, b, a}
+}
+
+// SetClearMask ... buffers to clear, a combination of gl.COLOR_BUFFER_BIT, gl.DEPTH_BUFFER_BIT and gl.STENCIL_BUFFER_BIT
+func SetClearMask(mask uint32) {
+	clearMask = mask
+}
+
+func Clear() {
+	if clearMask == 0 {
+		return
+	}
+
+	gl.ClearColor(clearColour[0], clearColour[1], clearColour[2], clearColour[3])
+	gl.Clear(clearMask)
+}
@@ -42,6 +42,7 @@ type Texture struct {
 	height      int
 	file        string
 	textureUnit uint32
+	pixels      *image.RGBA // Kept for reading alpha on the cpu
 }
 
 /**
@@ -67,12 +68,19 @@ func LoadTexture(file string) *Texture {
 		panic(fmt.Errorf("Image load error, error: %v", err))
 	}
 
+	return CreateTexture(file, img)
+}
+
+/*
+Creates a texture from image data already in memory, file is the name used to find the texture with FindTex.
+*/
+func CreateTexture(file string, img image.Image) *Texture {
 	bounds := img.Bounds()
 	rgba := image.NewRGBA(bounds)
 	if rgba.Stride != rgba.Rect.Size().X*4 {
 		panic(fmt.Errorf("unsupported stride"))
 	}
-	draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
 
 	var texture uint32
 	gl.ActiveTexture(currentTextureUnit())
@@ -95,10 +103,11 @@ func LoadTexture(file string) *Texture {
 
 	textureObj := &Texture{
 		texture,
-		bounds.Max.X,
-		bounds.Max.Y,
+		bounds.Dx(),
+		bounds.Dy(),
 		file,
 		currentTextureUnitId,
+		rgba,
 	}
 
 	gl.BindTexture(gl.TEXTURE_2D, 0)
@@ -128,6 +137,52 @@ func (t *Texture) Use() {
 	gl.BindTexture(gl.TEXTURE_2D, t.id)
 }
 
+// SetSubImage ... replace the pixels of the texture from x, y (top left) with the image data
+func (t *Texture) SetSubImage(x, y int, img image.Image) {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	draw.Draw(t.pixels, image.Rect(x, y, x+bounds.Dx(), y+bounds.Dy()), rgba, image.Point{}, draw.Src)
+
+	gl.ActiveTexture(t.textureUnit)
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.TexSubImage2D(
+		gl.TEXTURE_2D,
+		0,
+		int32(x),
+		int32(y),
+		int32(bounds.Dx()),
+		int32(bounds.Dy()),
+		gl.RGBA,
+		gl.UNSIGNED_BYTE,
+		gl.Ptr(rgba.Pix))
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+func (t *Texture) Width() int {
+	return t.width
+}
+
+func (t *Texture) Height() int {
+	return t.height
+}
+
+func (t *Texture) File() string {
+	return t.file
+}
+
+// Alpha ... alpha of the pixel at the normalized texture coordinate u, v
+func (t *Texture) Alpha(u, v float32) uint8 {
+	x := int(u * float32(t.width))
+	y := int(v * float32(t.height))
+
+	if x < 0 || y < 0 || x >= t.width || y >= t.height {
+		return 0
+	}
+
+	return t.pixels.RGBAAt(x, y).A
+}
+
 // NormCoords ... normalize pixture texture coordinates
 func (t *Texture) PixToTex(texs []float32) []float32 {
 	normedTexs := make([]float32, len(texs))
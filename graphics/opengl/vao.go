@@ -17,15 +17,18 @@ import (
 
 const DEFAULT_VECTOR_SIZE = 2
 const DEFAULT_TEXS_SIZE = 2
+const DEFAULT_COLOUR_SIZE = 4
 
 type VAO struct {
 	ID                        uint32
 	vertID                    uint32
 	texID                     uint32
 	rotGroupID                uint32
+	colourID                  uint32
 	windowWidth, windowHeight float32
 	verts                     []float32
 	texs                      []float32
+	colours                   []float32    // Per vertex colour, multiplied with the texture colour
 	rotGroups                 []mgl32.Vec4 // Grouped rotations
 	rot                       mgl32.Vec4   // Global VAO rotation
 	trans                     mgl32.Vec2   // Global VAO translation, individual translation should be performed on each vertex
@@ -34,22 +37,28 @@ type VAO struct {
 	created, defaultShader    bool
 	Texture                   *Texture
 	uniforms                  map[string]interface{}
-	cam                       mgl32.Vec2
+	cam                       mgl32.Vec4 // Camera position and rotation as x, y, cos, sin
 	zoom                      float32
+	modifiedStart             int // Range of vertices whose position has changed since TakeModified
+	modifiedEnd               int
 }
 
+// createdVAOs ... every vao which hasn't been deleted, used to update them all when the window is resized
+var createdVAOs []*VAO
+
 /*
 VBO creation and modification functions
 */
 
 //CreateVAO ... size of vao in vertices.
 func CreateVAO(size uint32, textureSource string, defaultShader bool, width float32, height float32) *VAO {
-	var vaoID, vertID, rotGroupID, texID uint32
+	var vaoID, vertID, rotGroupID, texID, colourID uint32
 
 	gl.GenVertexArrays(1, &vaoID)
 	gl.GenBuffers(1, &vertID)
 	gl.GenBuffers(1, &texID)
 	gl.GenBuffers(1, &rotGroupID)
+	gl.GenBuffers(1, &colourID)
 
 	var program *Program
 
@@ -59,10 +68,12 @@ func CreateVAO(size uint32, textureSource string, defaultShader bool, width floa
 		vertID,
 		texID,
 		rotGroupID,
+		colourID,
 		width,
 		height,
 		make([]float32, size*DEFAULT_VECTOR_SIZE),
 		make([]float32, size*DEFAULT_TEXS_SIZE),
+		whiteColours(size),
 		make([]mgl32.Vec4, size),
 		mgl32.Vec4{},
 		mgl32.Vec2{},
@@ -72,11 +83,14 @@ func CreateVAO(size uint32, textureSource string, defaultShader bool, width floa
 		defaultShader,
 		texture,
 		make(map[string]interface{}),
-		mgl32.Vec2{},
+		mgl32.Vec4{0, 0, 1, 0},
 		1,
+		0,
+		0,
 	}
 
 	vao.DefaultShader()
+	createdVAOs = append(createdVAOs, vao)
 
 	return vao
 }
@@ -91,6 +105,7 @@ func (vao *VAO) CreateBuffers() {
 	}
 
 	vao.created = true
+	vao.markModified(0, int(vao.vertNum))
 
 	gl.BindVertexArray(vao.ID)
 
@@ -114,6 +129,12 @@ func (vao *VAO) CreateBuffers() {
 	texAttrib := vao.shader.EnableAttribute("verttexcoord")
 	gl.VertexAttribPointer(texAttrib, DEFAULT_TEXS_SIZE, gl.FLOAT, false, 0, nil)
 
+	//colour buffer
+	gl.BindBuffer(gl.ARRAY_BUFFER, vao.colourID)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(vao.colours), gl.Ptr(vao.colours), gl.DYNAMIC_DRAW)
+	colourAttrib := vao.shader.EnableAttribute("vertcolour")
+	gl.VertexAttribPointer(colourAttrib, DEFAULT_COLOUR_SIZE, gl.FLOAT, false, 0, nil)
+
 	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 	gl.BindVertexArray(0)
 }
@@ -125,6 +146,8 @@ func (vao *VAO) UpdateBuffers() {
 		return
 	}
 
+	vao.markModified(0, int(vao.vertNum))
+
 	gl.BindVertexArray(vao.ID)
 	// Verts
 	gl.BindBuffer(gl.ARRAY_BUFFER, vao.vertID)
@@ -139,6 +162,10 @@ func (vao *VAO) UpdateBuffers() {
 	gl.BindBuffer(gl.ARRAY_BUFFER, vao.texID)
 	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(vao.texs), gl.Ptr(vao.texs))
 
+	// Colours
+	gl.BindBuffer(gl.ARRAY_BUFFER, vao.colourID)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(vao.colours), gl.Ptr(vao.colours))
+
 	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 	gl.BindVertexArray(0)
 }
@@ -173,26 +200,93 @@ func (vao *VAO) UpdateTexBufferData(texData []float32) {
 	vao.UpdateBuffers()
 }
 
+func (vao *VAO) UpdateColourBufferData(colourData []float32) {
+	vao.colours = colourData
+	vao.UpdateBuffers()
+}
+
+/*
+Index updates only upload the modified range of the buffer, index is the first vertex to update.
+*/
+
 func (vao *VAO) UpdateVertBufferIndex(index int, vertData []float32) {
+	vao.markModified(index, index+len(vertData)/DEFAULT_VECTOR_SIZE)
+
 	index *= DEFAULT_VECTOR_SIZE
+	copy(vao.verts[index:], vertData)
+
+	vao.updateBufferRange(vao.vertID, index, vao.verts[index:index+len(vertData)])
+}
+
+func (vao *VAO) UpdateTexBufferIndex(index int, texData []float32) {
+	index *= DEFAULT_TEXS_SIZE
+	copy(vao.texs[index:], texData)
+
+	vao.updateBufferRange(vao.texID, index, vao.texs[index:index+len(texData)])
+}
 
-	for i, val := range vertData {
-		vao.verts[index+i] = val
+// UpdateColourBufferIndex ... colourData is 4 floats (rgba) per vertex
+func (vao *VAO) UpdateColourBufferIndex(index int, colourData []float32) {
+	index *= DEFAULT_COLOUR_SIZE
+	copy(vao.colours[index:], colourData)
+
+	vao.updateBufferRange(vao.colourID, index, vao.colours[index:index+len(colourData)])
+}
+
+// UpdateRotGroupBufferIndex ... upload the grouped rotations of vertices start to end
+func (vao *VAO) UpdateRotGroupBufferIndex(start, end int) {
+	vao.markModified(start, end)
+
+	rotGroups := destructureVecArray(vao.rotGroups[start:end])
+
+	vao.updateBufferRange(vao.rotGroupID, start*4, rotGroups)
+}
+
+// updateBufferRange ... offset is in floats from the start of the buffer
+func (vao *VAO) updateBufferRange(buffer uint32, offset int, data []float32) {
+	// Creation uploads the entire buffer
+	if !vao.created {
+		vao.CreateBuffers()
+
+		return
 	}
 
-	vao.UpdateBuffers()
+	if len(data) == 0 {
+		return
+	}
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, buffer)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 4*offset, 4*len(data), gl.Ptr(data))
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 }
 
-// TODO add proper index update handling, currently non functional
+// markModified ... extend the modified range to include vertices start to end
+func (vao *VAO) markModified(start, end int) {
+	if start >= end {
+		return
+	}
 
-func (vao *VAO) UpdateTexBufferIndex(index int, texData []float32) {
-	index *= DEFAULT_TEXS_SIZE
+	if vao.modifiedStart == vao.modifiedEnd {
+		vao.modifiedStart, vao.modifiedEnd = start, end
 
-	for i, val := range texData {
-		vao.texs[index+i] = val
+		return
 	}
 
-	vao.UpdateBuffers()
+	if start < vao.modifiedStart {
+		vao.modifiedStart = start
+	}
+
+	if end > vao.modifiedEnd {
+		vao.modifiedEnd = end
+	}
+}
+
+// TakeModified ... range of vertices whose position or grouped rotation has been uploaded since the last call, start == end if none
+func (vao *VAO) TakeModified() (start, end int) {
+	start, end = vao.modifiedStart, vao.modifiedEnd
+	vao.modifiedStart, vao.modifiedEnd = 0, 0
+
+	return start, end
 }
 
 // SetData ... set the vert/tex data of the vao, does not update the buffer
@@ -239,8 +333,9 @@ func (vao *VAO) SetTranslation(x, y float32) {
 	vao.shader.SetUniform("trans", vao.trans)
 }
 
-func (vao *VAO) SetCamera(x, y float32) {
-	vao.cam = mgl32.Vec2{x, y}
+// SetCamera ... x, y is the world position at the top left of the screen, rotation is about the centre of the screen
+func (vao *VAO) SetCamera(x, y, rad float32) {
+	vao.cam = mgl32.Vec4{x, y, float32(math.Cos(float64(rad))), float32(math.Sin(float64(rad)))}
 	vao.shader.SetUniform("cam", vao.cam)
 }
 
@@ -249,10 +344,72 @@ func (vao *VAO) SetZoom(z float32) {
 	vao.shader.SetUniform("zoom", vao.zoom)
 }
 
+// SetDimensions ... size in pixels of the view the vao is drawn to
+func (vao *VAO) SetDimensions(width, height float32) {
+	vao.windowWidth = width
+	vao.windowHeight = height
+	vao.shader.SetUniform("dim", mgl32.Vec2{width, height})
+}
+
+// SetAllDimensions ... set the dimensions of every vao, eg when the window is resized
+func SetAllDimensions(width, height float32) {
+	for _, vao := range createdVAOs {
+		vao.SetDimensions(width, height)
+	}
+}
+
+/*
+Vertex data access, values are as set before any transformation in the shader
+*/
+
+// Vert ... position of vertex index in pixels
+func (vao *VAO) Vert(index int) (x, y float32) {
+	return vao.verts[index*DEFAULT_VECTOR_SIZE], vao.verts[index*DEFAULT_VECTOR_SIZE+1]
+}
+
+// TexCoord ... normalized texture coordinate of vertex index
+func (vao *VAO) TexCoord(index int) (u, v float32) {
+	return vao.texs[index*DEFAULT_TEXS_SIZE], vao.texs[index*DEFAULT_TEXS_SIZE+1]
+}
+
+// TexCoords ... normalized texture coordinates of vertices start to end, must not be modified
+func (vao *VAO) TexCoords(start, end int) []float32 {
+	return vao.texs[start*DEFAULT_TEXS_SIZE : end*DEFAULT_TEXS_SIZE]
+}
+
+// Colours ... colours of vertices start to end, must not be modified
+func (vao *VAO) Colours(start, end int) []float32 {
+	return vao.colours[start*DEFAULT_COLOUR_SIZE : end*DEFAULT_COLOUR_SIZE]
+}
+
+// GroupedRotation ... grouped rotation of vertex index as x, y, cos, sin
+func (vao *VAO) GroupedRotation(index int) mgl32.Vec4 {
+	return vao.rotGroups[index]
+}
+
+// Rotation ... global rotation as x, y, cos, sin
+func (vao *VAO) Rotation() mgl32.Vec4 {
+	return vao.rot
+}
+
+// UsesDefaultShader ... if the vao was created to be drawn with the default shader
+func (vao *VAO) UsesDefaultShader() bool {
+	return vao.defaultShader
+}
+
 func (vao *VAO) Delete() {
 	gl.DeleteBuffers(1, &vao.vertID)
 	gl.DeleteBuffers(1, &vao.texID)
+	gl.DeleteBuffers(1, &vao.rotGroupID)
+	gl.DeleteBuffers(1, &vao.colourID)
 	gl.DeleteVertexArrays(1, &vao.ID)
+
+	for i, created := range createdVAOs {
+		if created == vao {
+			createdVAOs = append(createdVAOs[:i], createdVAOs[i+1:]...)
+			break
+		}
+	}
 }
 
 /*
@@ -288,17 +445,18 @@ func (vao *VAO) DefaultShader() Program {
 	// Currently unusued, optimized out by the shader compiler so will fail
 	program.AddAttribute("rotgroup")
 	program.AddAttribute("verttexcoord")
+	program.AddAttribute("vertcolour")
 
 	// Add and set rotation uniform
 	vao.AddUniform("rot", mgl32.Vec4{})
 	vao.SetRotation(0, 0, 0)
 
 	// Other uniforms can use default values.
-	var zoom float32 = 1.5
+	var zoom float32 = 1
 
 	vao.AddUniform("trans", mgl32.Vec2{})
 	vao.AddUniform("dim", mgl32.Vec2{vao.windowWidth, vao.windowHeight})
-	vao.AddUniform("cam", mgl32.Vec2{})
+	vao.AddUniform("cam", mgl32.Vec4{0, 0, 1, 0})
 	vao.AddUniform("zoom", zoom)
 
 	return *program
@@ -339,3 +497,14 @@ func destructureVecArray(vecs []mgl32.Vec4) []float32 {
 
 	return vecFloats
 }
+
+// whiteColours ... default colour data, leaves the texture colour unchanged
+func whiteColours(size uint32) []float32 {
+	colours := make([]float32, size*DEFAULT_COLOUR_SIZE)
+
+	for i := range colours {
+		colours[i] = 1
+	}
+
+	return colours
+}
@@ -0,0 +1,383 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+	"math"
+	"math/rand"
+	"time"
+	"unsafe"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Particle systems, a fixed capacity pool of quads simulated on the main thread at the start of each render.
+Each system owns a render object, particles are compacted every update so only the first alive*6 vertices are drawn.
+Dead particles are recycled and when the pool is full new particles are dropped, no memory is allocated per particle.
+*/
+
+type EmitMode int
+
+const (
+	// EmitRate ... emit Rate particles per second while the emitter is active
+	EmitRate EmitMode = iota
+	// EmitBurst ... emit Count particles each time Burst is called
+	EmitBurst
+)
+
+/*
+Emitter ... configuration of how particles are spawned, all positions and sizes are in pixels and angles in radians.
+Variance fields randomise the value by up to plus or minus the variance.
+Emitters added to a system must only be modified on the main thread, use the Job methods otherwise.
+*/
+type Emitter struct {
+	X, Y   float32
+	Mode   EmitMode
+	Active bool
+	Rate   float32
+	Count  int
+
+	Lifetime, LifetimeVariance time.Duration
+
+	// Angle ... direction particles are emitted in, 0 is to the right and positive is clockwise
+	Angle, Spread              float32
+	Speed, SpeedVariance       float32
+	GravityX, GravityY         float32
+	Rotation, RotationVariance float32
+	Spin, SpinVariance         float32
+
+	// Colour and size are interpolated from start to end over each particles lifetime
+	StartColour, EndColour mgl32.Vec4
+	StartSize, EndSize     float32
+
+	// TexRect ... texture of each particle, the whole texture is used if it has no width
+	TexRect TexRect
+
+	pending int
+	owed    float32
+}
+
+type particle struct {
+	emitter       *Emitter
+	x, y          float32
+	vx, vy        float32
+	rot, spin     float32
+	age, lifetime float32
+}
+
+type ParticleSystem struct {
+	obj       RenderObject
+	particles []particle
+	alive     int
+	emitters  []*Emitter
+
+	// Per vertex data reused each update
+	verts, texs, colours []float32
+}
+
+var particleSystems []*ParticleSystem
+var lastParticleUpdate time.Time
+var particleRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// CreateEmitter ... a rate emitter at x, y with white particles, the remaining fields should be set before it is added
+func CreateEmitter(x, y float32) *Emitter {
+	return &Emitter{
+		X:           x,
+		Y:           y,
+		Mode:        EmitRate,
+		Active:      true,
+		Lifetime:    time.Second,
+		StartColour: mgl32.Vec4{1, 1, 1, 1},
+		EndColour:   mgl32.Vec4{1, 1, 1, 0},
+		StartSize:   8,
+		EndSize:     8,
+	}
+}
+
+// CreateParticleSystem ... capacity is the maximum number of particles alive at once
+func CreateParticleSystem(capacity int, texture string) *ParticleSystem {
+	ps := &ParticleSystem{}
+	ps.init(capacity, texture)
+
+	return ps
+}
+
+func (ps *ParticleSystem) init(capacity int, texture string) {
+	CreateRenderObject(&ps.obj, capacity*6, texture, true)
+
+	ps.particles = make([]particle, capacity)
+	ps.verts = make([]float32, capacity*6*opengl.DEFAULT_VECTOR_SIZE)
+	ps.texs = make([]float32, capacity*6*opengl.DEFAULT_TEXS_SIZE)
+	ps.colours = make([]float32, capacity*6*opengl.DEFAULT_COLOUR_SIZE)
+
+	particleSystems = append(particleSystems, ps)
+}
+
+func (ps *ParticleSystem) RenderObject() *RenderObject {
+	return &ps.obj
+}
+
+func (ps *ParticleSystem) Alive() int {
+	return ps.alive
+}
+
+func (ps *ParticleSystem) Capacity() int {
+	return len(ps.particles)
+}
+
+func (ps *ParticleSystem) AddEmitter(e *Emitter) {
+	ps.emitters = append(ps.emitters, e)
+}
+
+// RemoveEmitter ... stop the emitter spawning, particles already emitted live out their lifetime
+func (ps *ParticleSystem) RemoveEmitter(e *Emitter) {
+	for i, emitter := range ps.emitters {
+		if emitter == e {
+			ps.emitters = append(ps.emitters[:i], ps.emitters[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// Burst ... emit Count particles on the next update
+func (e *Emitter) Burst() {
+	e.pending += e.Count
+}
+
+func (e *Emitter) SetPosition(x, y float32) {
+	e.X = x
+	e.Y = y
+}
+
+/*
+Simulation, performed at the start of each render
+*/
+
+func updateParticleSystems() {
+	now := time.Now()
+
+	if lastParticleUpdate.IsZero() {
+		lastParticleUpdate = now
+	}
+
+	delta := float32(now.Sub(lastParticleUpdate).Seconds())
+	lastParticleUpdate = now
+
+	for _, ps := range particleSystems {
+		ps.Update(delta)
+	}
+}
+
+// Update ... advance the system by delta seconds and upload the alive particles
+func (ps *ParticleSystem) Update(delta float32) {
+	for _, e := range ps.emitters {
+		ps.emit(e, delta)
+	}
+
+	for i := 0; i < ps.alive; {
+		p := &ps.particles[i]
+		p.age += delta
+
+		if p.age >= p.lifetime {
+			// Recycle by moving the last alive particle into this slot
+			ps.alive--
+			ps.particles[i] = ps.particles[ps.alive]
+
+			continue
+		}
+
+		p.vx += p.emitter.GravityX * delta
+		p.vy += p.emitter.GravityY * delta
+		p.x += p.vx * delta
+		p.y += p.vy * delta
+		p.rot += p.spin * delta
+		i++
+	}
+
+	ps.upload()
+}
+
+func (ps *ParticleSystem) emit(e *Emitter, delta float32) {
+	count := e.pending
+	e.pending = 0
+
+	if e.Mode == EmitRate && e.Active {
+		e.owed += e.Rate * delta
+		count += int(e.owed)
+		e.owed -= float32(int(e.owed))
+	}
+
+	for ; count > 0 && ps.alive < len(ps.particles); count-- {
+		angle := float64(vary(e.Angle, e.Spread))
+		speed := vary(e.Speed, e.SpeedVariance)
+		lifetime := e.Lifetime + time.Duration(varyUnit()*float32(e.LifetimeVariance))
+
+		ps.particles[ps.alive] = particle{
+			e,
+			e.X,
+			e.Y,
+			speed * float32(math.Cos(angle)),
+			speed * float32(math.Sin(angle)),
+			vary(e.Rotation, e.RotationVariance),
+			vary(e.Spin, e.SpinVariance),
+			0,
+			float32(lifetime.Seconds()),
+		}
+		ps.alive++
+	}
+}
+
+// upload ... write the alive particles into the render object, the draw is limited to the alive particles
+func (ps *ParticleSystem) upload() {
+	vao := ps.obj.vao
+	texWidth := float32(ps.obj.texture.Width())
+	texHeight := float32(ps.obj.texture.Height())
+
+	for i := 0; i < ps.alive; i++ {
+		p := &ps.particles[i]
+		e := p.emitter
+		t := p.age / p.lifetime
+
+		half := (e.StartSize + (e.EndSize-e.StartSize)*t) / 2
+		left, top := p.x-half, p.y-half
+		right, bottom := p.x+half, p.y+half
+
+		texRect := e.TexRect
+		if texRect.Width == 0 {
+			texRect = TexRect{0, 0, texWidth, texHeight}
+		}
+
+		u0, v0 := texRect.X/texWidth, texRect.Y/texHeight
+		u1, v1 := (texRect.X+texRect.Width)/texWidth, (texRect.Y+texRect.Height)/texHeight
+
+		copy(ps.verts[i*12:], []float32{left, top, right, top, right, bottom, left, top, right, bottom, left, bottom})
+		copy(ps.texs[i*12:], []float32{u0, v0, u1, v0, u1, v1, u0, v0, u1, v1, u0, v1})
+
+		colour := e.StartColour.Add(e.EndColour.Sub(e.StartColour).Mul(t))
+		for v := 0; v < 6; v++ {
+			copy(ps.colours[(i*6+v)*opengl.DEFAULT_COLOUR_SIZE:], colour[:])
+		}
+
+		vao.SetGroupedRotation(p.x, p.y, p.rot, i*6, i*6+6)
+	}
+
+	vertNum := ps.alive * 6
+
+	vao.UpdateVertBufferIndex(0, ps.verts[:vertNum*opengl.DEFAULT_VECTOR_SIZE])
+	vao.UpdateTexBufferIndex(0, ps.texs[:vertNum*opengl.DEFAULT_TEXS_SIZE])
+	vao.UpdateColourBufferIndex(0, ps.colours[:vertNum*opengl.DEFAULT_COLOUR_SIZE])
+	vao.UpdateRotGroupBufferIndex(0, vertNum)
+	ps.obj.freeVert = vertNum
+}
+
+// vary ... value randomised by up to plus or minus variance
+func vary(value, variance float32) float32 {
+	return value + varyUnit()*variance
+}
+
+// varyUnit ... random value between -1 and 1
+func varyUnit() float32 {
+	return particleRand.Float32()*2 - 1
+}
+
+/*
+Particle system jobs, the system or emitter is passed as the first parameter.
+*/
+
+func callCreateParticleSystem(job RenderObjectJob) {
+	params := job.params
+
+	(*ParticleSystem)(job.retVal).init(params[0].(int), params[1].(string))
+}
+
+func callAddEmitter(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*ParticleSystem).AddEmitter(params[1].(*Emitter))
+}
+
+func callRemoveEmitter(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*ParticleSystem).RemoveEmitter(params[1].(*Emitter))
+}
+
+func callBurst(job RenderObjectJob) {
+	job.params[0].(*Emitter).Burst()
+}
+
+func callEmitterSetPosition(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Emitter).SetPosition(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+func callEmitterSetActive(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Emitter).Active = params[1].(bool)
+}
+
+// CreateParticleSystemJob ... the returned system is populated once the job has been performed, its Job methods can be used immediately
+func CreateParticleSystemJob(capacity int, texture string) *ParticleSystem {
+	ps := &ParticleSystem{}
+
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{capacity, texture},
+		unsafe.Pointer(ps),
+		callCreateParticleSystem,
+	}
+
+	return ps
+}
+
+// AddEmitterJob ... the emitter must not be modified outside of jobs once this is called
+func (ps *ParticleSystem) AddEmitterJob(e *Emitter) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{ps, e},
+		nil,
+		callAddEmitter,
+	}
+}
+
+func (ps *ParticleSystem) RemoveEmitterJob(e *Emitter) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{ps, e},
+		nil,
+		callRemoveEmitter,
+	}
+}
+
+func (e *Emitter) BurstJob() {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{e},
+		nil,
+		callBurst,
+	}
+}
+
+func (e *Emitter) SetPositionJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{e, x, y},
+		nil,
+		callEmitterSetPosition,
+	}
+}
+
+func (e *Emitter) SetActiveJob(active bool) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{e, active},
+		nil,
+		callEmitterSetActive,
+	}
+}
@@ -0,0 +1,190 @@
+package graphics
+
+import (
+	"unsafe"
+)
+
+/*
+Picking, finds the shape drawn at a point on the screen. Shapes are the 6 vertex rectangles added by AddSquare/AddRect
+(and sprites, text, nine slices etc), they are tested with the same transformations as the vertex shader.
+Render objects are tested from the top layer down and shapes from the last drawn to the first.
+*/
+
+// Picked ... Obj is nil if nothing was picked, Index is the index of the shapes first vertex
+type Picked struct {
+	Obj   *RenderObject
+	Index int
+}
+
+/*
+Pick ... find the shape at screen position x, y (eg MouseX, MouseY), if alphaTest is set transparent pixels are ignored.
+When viewports are used the viewport at x, y is picked from, using its camera.
+*/
+func Pick(x, y float32, alphaTest bool) Picked {
+	if len(viewports) == 0 {
+		return pickObjects(x, y, windowView(camera), nil, alphaTest)
+	}
+
+	// Viewports drawn last are on top
+	for i := len(viewports) - 1; i >= 0; i-- {
+		vp := viewports[i]
+
+		if !vp.Contains(x, y) {
+			continue
+		}
+
+		if picked := pickObjects(x-vp.x, y-vp.y, vp.view(), vp, alphaTest); picked.Obj != nil {
+			return picked
+		}
+	}
+
+	return Picked{nil, -1}
+}
+
+// pickObjects ... pick from the render objects drawn by the viewport, all render objects if it is nil
+func pickObjects(x, y float32, v view, vp *Viewport, alphaTest bool) Picked {
+	for i := len(renderObjects) - 1; i >= 0; i-- {
+		obj := renderObjects[i]
+
+		if vp != nil && !vp.draws(obj) {
+			continue
+		}
+
+		if index := obj.pick(x, y, v, alphaTest); index != -1 {
+			return Picked{obj, index}
+		}
+	}
+
+	return Picked{nil, -1}
+}
+
+// Pick ... index of the top shape in the render object at screen position x, y using the current camera, -1 if there is none
+func (obj *RenderObject) Pick(x, y float32, alphaTest bool) int {
+	return obj.pick(x, y, windowView(camera), alphaTest)
+}
+
+func (obj *RenderObject) pick(x, y float32, v view, alphaTest bool) int {
+	if obj.invisible {
+		return -1
+	}
+
+	if obj.clipped() && !obj.clipBounds(v).contains(x, y) {
+		return -1
+	}
+
+	if !obj.screenSpace {
+		x, y = v.viewToWorld(x, y)
+	}
+
+	return pickShapes(obj.freeVert, x, y, obj.transformedVert, func(tri int, a, b, c float32) bool {
+		return !alphaTest || obj.texture.Alpha(obj.texCoordAt(tri, a, b, c)) != 0
+	})
+}
+
+/*
+pickShapes ... index of the top shape of the first freeVert vertices containing x, y, -1 if there is none.
+vert is the transformed position of a vertex, accept is called with the barycentric coordinates of x, y in the hit triangle
+and rejects the hit shape when it returns false, eg for transparent pixels.
+*/
+func pickShapes(freeVert int, x, y float32, vert func(index int) (x, y float32), accept func(tri int, a, b, c float32) bool) int {
+	for index := freeVert - 6; index >= 0; index -= 6 {
+		// Each triangle of the rectangle is tested as rotations may make it any quadrilateral
+		for tri := index; tri < index+6; tri += 3 {
+			var xs, ys [3]float32
+
+			for i := 0; i < 3; i++ {
+				xs[i], ys[i] = vert(tri + i)
+			}
+
+			a, b, c, hit := barycentric(xs, ys, x, y)
+
+			if !hit {
+				continue
+			}
+
+			if !accept(tri, a, b, c) {
+				break
+			}
+
+			return index
+		}
+	}
+
+	return -1
+}
+
+// barycentric ... barycentric coordinates of x, y in the triangle, hit is false outside it or if it has no area (eg hidden shapes)
+func barycentric(xs, ys [3]float32, x, y float32) (a, b, c float32, hit bool) {
+	denominator := (ys[1]-ys[2])*(xs[0]-xs[2]) + (xs[2]-xs[1])*(ys[0]-ys[2])
+	if denominator == 0 {
+		return 0, 0, 0, false
+	}
+
+	a = ((ys[1]-ys[2])*(x-xs[2]) + (xs[2]-xs[1])*(y-ys[2])) / denominator
+	b = ((ys[2]-ys[0])*(x-xs[2]) + (xs[0]-xs[2])*(y-ys[2])) / denominator
+	c = 1 - a - b
+
+	return a, b, c, a >= 0 && b >= 0 && c >= 0
+}
+
+// texCoordAt ... texture coordinate at the barycentric coordinates a, b, c of the triangle starting at vertex start
+func (obj *RenderObject) texCoordAt(start int, a, b, c float32) (u, v float32) {
+	u0, v0 := obj.vao.TexCoord(start)
+	u1, v1 := obj.vao.TexCoord(start + 1)
+	u2, v2 := obj.vao.TexCoord(start + 2)
+
+	return a*u0 + b*u1 + c*u2, a*v0 + b*v1 + c*v2
+}
+
+// transformedVert ... position of a vertex after the grouped rotation, global rotation and translation, as in the vertex shader
+func (obj *RenderObject) transformedVert(index int) (x, y float32) {
+	x, y = obj.localVert(index)
+
+	rot := obj.vao.Rotation()
+	x, y = rotateAbout(x, y, rot[0], rot[1], rot[2], rot[3])
+
+	return x + *obj.ptrVars[transXPtr], y + *obj.ptrVars[transYPtr]
+}
+
+// localVert ... position of a vertex after its grouped rotation
+func (obj *RenderObject) localVert(index int) (x, y float32) {
+	x, y = obj.vao.Vert(index)
+	rotGroup := obj.vao.GroupedRotation(index)
+
+	return rotateAbout(x, y, rotGroup[0], rotGroup[1], rotGroup[2], rotGroup[3])
+}
+
+func rotateAbout(x, y, centreX, centreY, cos, sin float32) (float32, float32) {
+	x -= centreX
+	y -= centreY
+
+	return cos*x - sin*y + centreX, sin*x + cos*y + centreY
+}
+
+/*
+Pick jobs
+*/
+
+func callPick(job RenderObjectJob) {
+	params := job.params
+
+	*(*Picked)(job.retVal) = Pick(
+		params[0].(float32),
+		params[1].(float32),
+		params[2].(bool),
+	)
+}
+
+// PickJob ... the result is populated once the job has been performed
+func PickJob(x, y float32, alphaTest bool) *Picked {
+	picked := &Picked{nil, -1}
+
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{x, y, alphaTest},
+		unsafe.Pointer(picked),
+		callPick,
+	}
+
+	return picked
+}
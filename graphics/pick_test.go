@@ -0,0 +1,119 @@
+package graphics
+
+import (
+	"math"
+	"testing"
+)
+
+// rectVerts ... the 6 vertices of a rectangle in the same order as AddRect
+func rectVerts(x, y, width, height float32) []float32 {
+	return rectTexs([2]float32{x, y}, [2]float32{x + width, y}, [2]float32{x + width, y + height}, [2]float32{x, y + height})
+}
+
+// vertsOf ... vertex positions of verts for pickShapes
+func vertsOf(verts []float32) func(index int) (x, y float32) {
+	return func(index int) (x, y float32) {
+		return verts[index*2], verts[index*2+1]
+	}
+}
+
+func TestBarycentric(t *testing.T) {
+	xs, ys := [3]float32{0, 10, 10}, [3]float32{0, 0, 10}
+
+	tests := []struct {
+		name    string
+		xs, ys  [3]float32
+		x, y    float32
+		wantHit bool
+	}{
+		{"inside", xs, ys, 8, 2, true},
+		{"corner", xs, ys, 0, 0, true},
+		{"edge", xs, ys, 5, 5, true},
+		{"outside", xs, ys, 2, 8, false},
+		{"beyond", xs, ys, 11, 5, false},
+		{"no area", [3]float32{}, [3]float32{}, 0, 0, false},
+	}
+
+	for _, test := range tests {
+		a, b, c, hit := barycentric(test.xs, test.ys, test.x, test.y)
+
+		if hit != test.wantHit {
+			t.Errorf("%s: hit %v, want %v", test.name, hit, test.wantHit)
+			continue
+		}
+
+		if !hit {
+			continue
+		}
+
+		// The coordinates weight the vertices to give back x, y
+		x := a*test.xs[0] + b*test.xs[1] + c*test.xs[2]
+		y := a*test.ys[0] + b*test.ys[1] + c*test.ys[2]
+
+		if math.Abs(float64(x-test.x)) > 1e-4 || math.Abs(float64(y-test.y)) > 1e-4 {
+			t.Errorf("%s: coordinates %v, %v, %v give %v, %v", test.name, a, b, c, x, y)
+		}
+	}
+}
+
+func TestPickShapes(t *testing.T) {
+	// Shape 0 from 0, 0 to 20, 20 drawn under shape 1 from 10, 10 to 30, 30
+	verts := append(rectVerts(0, 0, 20, 20), rectVerts(10, 10, 20, 20)...)
+	hidden := append(rectVerts(0, 0, 20, 20), make([]float32, 12)...)
+
+	// Rotated 90 degrees clockwise about 20, 20, shape 1 then covers 10, 10 to 30, 30 and shape 0 20, 0 to 40, 20
+	rotated := func(index int) (x, y float32) {
+		return rotateAbout(verts[index*2], verts[index*2+1], 20, 20, 0, 1)
+	}
+
+	all := func(tri int, a, b, c float32) bool { return true }
+	opaqueBelow := func(tri int, a, b, c float32) bool { return tri < 6 }
+
+	tests := []struct {
+		name     string
+		freeVert int
+		vert     func(index int) (x, y float32)
+		accept   func(tri int, a, b, c float32) bool
+		x, y     float32
+		want     int
+	}{
+		{"top shape", 12, vertsOf(verts), all, 15, 15, 6},
+		{"bottom shape", 12, vertsOf(verts), all, 5, 5, 0},
+		{"top shape only", 12, vertsOf(verts), all, 25, 25, 6},
+		{"miss", 12, vertsOf(verts), all, 35, 5, -1},
+		{"unused vertices", 6, vertsOf(verts), all, 25, 25, -1},
+		{"hidden top shape", 12, vertsOf(hidden), all, 15, 15, 0},
+		{"rotated", 12, rotated, all, 35, 5, 0},
+		{"rotated miss", 12, rotated, all, 5, 5, -1},
+		{"transparent top shape", 12, vertsOf(verts), opaqueBelow, 15, 15, 0},
+		{"transparent top shape only", 12, vertsOf(verts), opaqueBelow, 25, 25, -1},
+	}
+
+	for _, test := range tests {
+		if got := pickShapes(test.freeVert, test.x, test.y, test.vert, test.accept); got != test.want {
+			t.Errorf("%s: got %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPickSkipsObjects(t *testing.T) {
+	saved := renderObjects
+	defer func() { renderObjects = saved }()
+
+	tests := []struct {
+		name string
+		obj  *RenderObject
+	}{
+		{"invisible", &RenderObject{invisible: true, freeVert: 6}},
+		{"clipped out", &RenderObject{screenSpace: true, freeVert: 6, clip: clipping{rect: &rect{0, 0, 10, 10}}}},
+		{"no shapes", &RenderObject{screenSpace: true}},
+	}
+
+	for _, test := range tests {
+		renderObjects = []*RenderObject{test.obj}
+
+		if got := pickObjects(50, 50, windowView(camera), nil, false); got.Obj != nil || got.Index != -1 {
+			t.Errorf("%s: got %+v, want nothing picked", test.name, got)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package graphics
+
+import (
+	"Gopengl/util"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+/*
+Named texture regions, so sprite sheet layouts are kept out of game code. Regions are registered per texture file,
+either in code with DefineRegion or in a sidecar JSON file next to the texture with the same name, eg ./sprites/ships.json
+for ./sprites/ships.png:
+
+	{"regions": {"ship_carrier": {"x": 0, "y": 0, "width": 64, "height": 32}}}
+
+The sidecar is loaded the first time a region of the texture is looked up, regions defined in code are kept over those in
+the sidecar. Regions can be defined and loaded from any go routine.
+*/
+
+type regionFile struct {
+	Regions map[string]TexRect `json:"regions"`
+}
+
+var (
+	regionMutex sync.Mutex
+	// regions ... regions of each texture file by name
+	regions = make(map[string]map[string]TexRect)
+	// sidecarsLoaded ... texture files whose sidecar has been looked for
+	sidecarsLoaded = make(map[string]bool)
+)
+
+// DefineRegion ... register a named rectangle of the texture file
+func DefineRegion(texture, name string, rect TexRect) {
+	regionMutex.Lock()
+	defer regionMutex.Unlock()
+
+	textureRegions(texture)[name] = rect
+}
+
+// LoadRegions ... load the sidecar of the texture file now, it must exist
+func LoadRegions(texture string) {
+	regionMutex.Lock()
+	defer regionMutex.Unlock()
+
+	loadSidecar(texture, true)
+}
+
+// Region ... the named rectangle of the texture file, panics if it has not been defined
+func Region(texture, name string) TexRect {
+	regionMutex.Lock()
+	defer regionMutex.Unlock()
+
+	if !sidecarsLoaded[texture] {
+		loadSidecar(texture, false)
+	}
+
+	rect, ok := regions[texture][name]
+	if !ok {
+		panic(fmt.Errorf("region %q not defined for texture %q", name, texture))
+	}
+
+	return rect
+}
+
+// SidecarFile ... file regions of the texture file are loaded from
+func SidecarFile(texture string) string {
+	return strings.TrimSuffix(texture, path.Ext(texture)) + ".json"
+}
+
+func textureRegions(texture string) map[string]TexRect {
+	if regions[texture] == nil {
+		regions[texture] = make(map[string]TexRect)
+	}
+
+	return regions[texture]
+}
+
+// loadSidecar ... regions already defined are kept, a missing sidecar is only an error if required
+func loadSidecar(texture string, required bool) {
+	sidecarsLoaded[texture] = true
+	file := SidecarFile(texture)
+
+	data, err := ioutil.ReadFile(util.RelativePath(file))
+	if os.IsNotExist(err) && !required {
+		return
+	}
+
+	if err != nil {
+		panic(fmt.Errorf("regions %q not found on disk: %v", file, err))
+	}
+
+	var rf regionFile
+
+	err = json.Unmarshal(data, &rf)
+	if err != nil {
+		panic(fmt.Errorf("Region error in %q, error: %v", file, err))
+	}
+
+	defined := textureRegions(texture)
+	for name, rect := range rf.Regions {
+		if _, exists := defined[name]; !exists {
+			defined[name] = rect
+		}
+	}
+}
+
+/*
+Render object region methods
+*/
+
+// AddRegionSprite ... add a sprite the size of the named region of the render objects texture, position is from the top left in pixels
+func (obj *RenderObject) AddRegionSprite(x, y float32, region string) *Sprite {
+	sprite := &Sprite{obj: obj}
+	obj.initRegionSprite(sprite, x, y, region)
+
+	return sprite
+}
+
+func (obj *RenderObject) initRegionSprite(sprite *Sprite, x, y float32, region string) {
+	rect := obj.Region(region)
+
+	obj.initSprite(sprite, x, y, rect.X, rect.Y, rect.Width, rect.Height, rect.Width, rect.Height)
+}
+
+// SetRegion ... set the texture of the rectangle at index to the named region, its vertices are unchanged
+func (obj *RenderObject) SetRegion(index int, region string) {
+	rect := obj.Region(region)
+
+	obj.ModifyTexRect(index, rect.X, rect.Y, rect.Width, rect.Height)
+}
+
+// Region ... the named region of the render objects texture
+func (obj *RenderObject) Region(name string) TexRect {
+	return Region(obj.texture.File(), name)
+}
+
+/*
+Region jobs
+*/
+
+func callAddRegionSprite(job RenderObjectJob) {
+	params := job.params
+
+	job.obj.initRegionSprite(
+		(*Sprite)(job.retVal),
+		params[0].(float32),
+		params[1].(float32),
+		params[2].(string),
+	)
+}
+
+func callSetRegion(job RenderObjectJob) {
+	params := job.params
+
+	job.obj.SetRegion(*params[0].(*int), params[1].(string))
+}
+
+// AddRegionSpriteJob ... the returned sprite is populated once the job has been performed, its Job methods can be used immediately
+func (obj *RenderObject) AddRegionSpriteJob(x, y float32, region string) *Sprite {
+	sprite := &Sprite{obj: obj}
+
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{x, y, region},
+		unsafe.Pointer(sprite),
+		callAddRegionSprite,
+	}
+
+	return sprite
+}
+
+func (obj *RenderObject) SetRegionJob(index *int, region string) {
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{index, region},
+		nil,
+		callSetRegion,
+	}
+}
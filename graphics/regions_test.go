@@ -0,0 +1,116 @@
+package graphics
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// useRegionDir ... write the sidecar files to a temporary root directory and forget any loaded regions
+func useRegionDir(t *testing.T, sidecars map[string]string) {
+	dir := t.TempDir()
+	t.Setenv("root_file_path", dir)
+
+	for file, data := range sidecars {
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	regions = make(map[string]map[string]TexRect)
+	sidecarsLoaded = make(map[string]bool)
+}
+
+// panics ... if f panics
+func panics(f func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+
+	f()
+
+	return false
+}
+
+func TestSidecarFile(t *testing.T) {
+	tests := []struct {
+		texture, want string
+	}{
+		{"./sprites/ships.png", "./sprites/ships.json"},
+		{"ships.tar.png", "ships.tar.json"},
+		{"./sprites/ships", "./sprites/ships.json"},
+	}
+
+	for _, test := range tests {
+		if got := SidecarFile(test.texture); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.texture, got, test.want)
+		}
+	}
+}
+
+func TestRegion(t *testing.T) {
+	useRegionDir(t, map[string]string{
+		"ships.json": `{"regions": {
+			"carrier": {"x": 0, "y": 0, "width": 64, "height": 32},
+			"frigate": {"x": 64, "y": 0, "width": 32, "height": 16}
+		}}`,
+	})
+
+	DefineRegion("ships.png", "frigate", TexRect{64, 16, 32, 16})
+	DefineRegion("ships.png", "scout", TexRect{96, 0, 16, 16})
+
+	tests := []struct {
+		name      string
+		want      TexRect
+		wantPanic bool
+	}{
+		{"carrier", TexRect{0, 0, 64, 32}, false},
+		{"frigate", TexRect{64, 16, 32, 16}, false},
+		{"scout", TexRect{96, 0, 16, 16}, false},
+		{"missing", TexRect{}, true},
+	}
+
+	for _, test := range tests {
+		var got TexRect
+
+		if panicked := panics(func() { got = Region("ships.png", test.name) }); panicked != test.wantPanic {
+			t.Errorf("%s: panicked %v, want %v", test.name, panicked, test.wantPanic)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLoadRegions(t *testing.T) {
+	tests := []struct {
+		name      string
+		sidecars  map[string]string
+		load      func()
+		wantPanic bool
+	}{
+		{"sidecar", map[string]string{"ships.json": `{"regions": {}}`}, func() { LoadRegions("ships.png") }, false},
+		{"missing sidecar", nil, func() { LoadRegions("ships.png") }, true},
+		{"missing optional sidecar", nil, func() { Region("ships.png", "carrier") }, true},
+		{"invalid sidecar", map[string]string{"ships.json": `{"regions": [`}, func() { LoadRegions("ships.png") }, true},
+		{"invalid region", map[string]string{"ships.json": `{"regions": {"carrier": {"x": "0"}}}`}, func() { LoadRegions("ships.png") }, true},
+	}
+
+	for _, test := range tests {
+		useRegionDir(t, test.sidecars)
+
+		if panicked := panics(test.load); panicked != test.wantPanic {
+			t.Errorf("%s: panicked %v, want %v", test.name, panicked, test.wantPanic)
+		}
+	}
+
+	// A missing optional sidecar only fails for the region which isn't defined
+	useRegionDir(t, nil)
+	DefineRegion("ships.png", "carrier", TexRect{0, 0, 64, 32})
+
+	if got := Region("ships.png", "carrier"); got != (TexRect{0, 0, 64, 32}) {
+		t.Errorf("got %v, want the defined region", got)
+	}
+}
@@ -0,0 +1,360 @@
+package graphics
+
+import (
+	"Gopengl/util"
+	"encoding/json"
+	"fmt"
+	"gopengl/graphics/opengl"
+	"io/ioutil"
+	"math"
+	"unsafe"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Scenes, every render object and the camera saved to a versioned JSON document so levels can be authored outside of go.
+Shapes are saved as their current vertices, texture coordinates (in texture pixels), colours and grouped rotations,
+so bitmap font text, nine slices and tile maps are loaded as plain shapes and animations are not saved.
+Particle systems and TrueType text are not saved, TrueType atlases are built at runtime so the text must be added again after loading.
+
+Fields which are usually left at their defaults are omitted, eg white colours and unrotated grouped rotations,
+so hand written documents only need the texture and the vertices of each shape.
+*/
+
+// SceneVersion ... version of the documents written by SaveScene, documents from later versions cannot be loaded
+const SceneVersion = 1
+
+type Scene struct {
+	Version int           `json:"version"`
+	Camera  SceneCamera   `json:"camera"`
+	Objects []SceneObject `json:"objects"`
+}
+
+type SceneCamera struct {
+	X        float32 `json:"x"`
+	Y        float32 `json:"y"`
+	Zoom     float32 `json:"zoom"`
+	Rotation float32 `json:"rotation"`
+}
+
+type SceneObject struct {
+	Texture string `json:"texture"`
+	// Capacity ... size of the render object in vertices, 0 for just enough for its shapes
+	Capacity        int              `json:"capacity,omitempty"`
+	CustomShader    bool             `json:"customShader,omitempty"`
+	Layer           int              `json:"layer,omitempty"`
+	ScreenSpace     bool             `json:"screenSpace,omitempty"`
+	Invisible       bool             `json:"invisible,omitempty"`
+	CullingDisabled bool             `json:"cullingDisabled,omitempty"`
+	TranslateX      float32          `json:"translateX,omitempty"`
+	TranslateY      float32          `json:"translateY,omitempty"`
+	Rotation        SceneRotation    `json:"rotation"`
+	Shapes          []SceneShape     `json:"shapes,omitempty"`
+	Instancing      *SceneInstancing `json:"instancing,omitempty"`
+}
+
+// SceneRotation ... rotation of rad radians about x, y
+type SceneRotation struct {
+	X   float32 `json:"x"`
+	Y   float32 `json:"y"`
+	Rad float32 `json:"rad"`
+}
+
+// SceneShape ... the 6 vertices of a shape in the same order as AddRect
+type SceneShape struct {
+	Verts     []float32 `json:"verts"`
+	TexCoords []float32 `json:"texCoords"`
+	// Colours ... 4 floats (rgba) per vertex, white if omitted
+	Colours []float32 `json:"colours,omitempty"`
+	// RotationGroups ... grouped rotation of each vertex, unrotated if omitted
+	RotationGroups []SceneRotation `json:"rotationGroups,omitempty"`
+	Hidden         bool            `json:"hidden,omitempty"`
+	// TexFlags ... texture flags of the shape, the texture coordinates are saved with the flags already applied
+	TexFlags TexFlag `json:"texFlags,omitempty"`
+}
+
+// SceneInstancing ... instance data of render objects created with CreateInstancedRenderObject
+type SceneInstancing struct {
+	Width     float32    `json:"width"`
+	Height    float32    `json:"height"`
+	Instances []Instance `json:"instances"`
+}
+
+// SaveScene ... write every render object and the current camera to file, must be called on the main thread
+func SaveScene(file string) {
+	data, err := json.MarshalIndent(CaptureScene(), "", "\t")
+	if err != nil {
+		panic(fmt.Errorf("Scene encoding error, error: %v", err))
+	}
+
+	err = ioutil.WriteFile(util.RelativePath(file), data, 0644)
+	if err != nil {
+		panic(fmt.Errorf("scene %q could not be written: %v", file, err))
+	}
+}
+
+// LoadScene ... read a scene document, this only reads the document so can be used from any go routine
+func LoadScene(file string) *Scene {
+	data, err := ioutil.ReadFile(util.RelativePath(file))
+	if err != nil {
+		panic(fmt.Errorf("scene %q not found on disk: %v", file, err))
+	}
+
+	s := &Scene{}
+
+	err = json.Unmarshal(data, s)
+	if err != nil {
+		panic(fmt.Errorf("Scene error in %q, error: %v", file, err))
+	}
+
+	if s.Version < 1 || s.Version > SceneVersion {
+		panic(fmt.Errorf("Scene %q has unsupported version %d", file, s.Version))
+	}
+
+	return s
+}
+
+// CaptureScene ... the current render objects and camera, must be called on the main thread
+func CaptureScene() *Scene {
+	s := &Scene{
+		Version: SceneVersion,
+		Camera: SceneCamera{
+			camera.x,
+			camera.y,
+			camera.zoom,
+			camera.rot,
+		},
+	}
+
+	for _, obj := range renderObjects {
+		if obj.ownedByParticleSystem() || obj.usesFontAtlas() {
+			continue
+		}
+
+		s.Objects = append(s.Objects, obj.sceneObject())
+	}
+
+	return s
+}
+
+/*
+Create ... create the render objects of the scene through CreateRenderObject and move the current camera,
+render objects are returned in the order they were saved.
+*/
+func (s *Scene) Create() []*RenderObject {
+	camera.SetPosition(s.Camera.X, s.Camera.Y)
+	camera.SetRotation(s.Camera.Rotation)
+
+	if s.Camera.Zoom > 0 {
+		camera.SetZoom(s.Camera.Zoom)
+	}
+
+	objs := make([]*RenderObject, len(s.Objects))
+
+	for i, so := range s.Objects {
+		objs[i] = &RenderObject{}
+		so.create(objs[i])
+	}
+
+	return objs
+}
+
+func (so SceneObject) create(obj *RenderObject) {
+	if so.Instancing != nil {
+		capacity := so.Capacity
+		if capacity == 0 {
+			capacity = len(so.Instancing.Instances)
+		}
+
+		CreateInstancedRenderObject(obj, capacity, so.Texture, so.Instancing.Width, so.Instancing.Height)
+		obj.SetInstances(so.Instancing.Instances)
+	} else {
+		capacity := so.Capacity
+		if capacity == 0 {
+			capacity = len(so.Shapes) * 6
+		}
+
+		CreateRenderObject(obj, capacity, so.Texture, !so.CustomShader)
+
+		for _, shape := range so.Shapes {
+			obj.addSceneShape(shape)
+		}
+	}
+
+	translateX, translateY := so.TranslateX, so.TranslateY
+	obj.SetTranslate(&translateX, &translateY)
+	obj.Rotate(so.Rotation.X, so.Rotation.Y, so.Rotation.Rad)
+	obj.SetScreenSpace(so.ScreenSpace)
+	obj.SetVisible(!so.Invisible)
+	obj.SetCulling(!so.CullingDisabled)
+	obj.SetLayer(so.Layer)
+}
+
+func (obj *RenderObject) addSceneShape(shape SceneShape) {
+	if len(shape.Verts) != 6*opengl.DEFAULT_VECTOR_SIZE || len(shape.TexCoords) != 6*opengl.DEFAULT_TEXS_SIZE {
+		panic("Scene shapes must have 6 vertices and texture coordinates")
+	}
+
+	if obj.freeVert+6 > obj.maxVert {
+		panic("Render Object Buffer overflow")
+	}
+
+	index := obj.freeVert
+	obj.vao.UpdateBufferIndex(index, shape.Verts, obj.texture.PixToTex(shape.TexCoords))
+	obj.freeVert += 6
+
+	if shape.Colours != nil {
+		obj.vao.UpdateColourBufferIndex(index, shape.Colours)
+	}
+
+	for i, rot := range shape.RotationGroups {
+		obj.SetGroupedRotation(rot.X, rot.Y, rot.Rad, index+i, index+i+1)
+	}
+
+	obj.setTexFlags(index, shape.TexFlags)
+
+	if shape.Hidden {
+		obj.Hide(index)
+	}
+}
+
+/*
+Scene capture
+*/
+
+func (obj *RenderObject) sceneObject() SceneObject {
+	rot := obj.vao.Rotation()
+
+	so := SceneObject{
+		Texture:         obj.texture.File(),
+		Capacity:        obj.maxVert,
+		CustomShader:    !obj.vao.UsesDefaultShader(),
+		Layer:           obj.layer,
+		ScreenSpace:     obj.screenSpace,
+		Invisible:       obj.invisible,
+		CullingDisabled: obj.cull.disabled,
+		TranslateX:      *obj.ptrVars[transXPtr],
+		TranslateY:      *obj.ptrVars[transYPtr],
+		Rotation:        sceneRotation(rot),
+	}
+
+	if obj.instanced != nil {
+		width, height := obj.instanced.vao.Size()
+		so.Capacity = obj.instanced.vao.Capacity()
+		so.CullingDisabled = false
+		so.Instancing = &SceneInstancing{width, height, make([]Instance, obj.instanced.count)}
+
+		for i := range so.Instancing.Instances {
+			so.Instancing.Instances[i] = obj.instance(i)
+		}
+
+		return so
+	}
+
+	for index := 0; index < obj.freeVert; index += 6 {
+		so.Shapes = append(so.Shapes, obj.sceneShape(index))
+	}
+
+	return so
+}
+
+func (obj *RenderObject) sceneShape(index int) SceneShape {
+	shape := SceneShape{
+		Verts:     make([]float32, 0, 6*opengl.DEFAULT_VECTOR_SIZE),
+		TexCoords: make([]float32, 0, 6*opengl.DEFAULT_TEXS_SIZE),
+		Hidden:    obj.Hidden(index),
+		TexFlags:  obj.texFlags[index],
+	}
+
+	texWidth := float32(obj.texture.Width())
+	texHeight := float32(obj.texture.Height())
+	white, unrotated := true, true
+
+	for vert := index; vert < index+6; vert++ {
+		x, y := obj.vao.Vert(vert)
+		if shape.Hidden {
+			x, y = obj.hidden[index][(vert-index)*2], obj.hidden[index][(vert-index)*2+1]
+		}
+
+		u, v := obj.vao.TexCoord(vert)
+
+		shape.Verts = append(shape.Verts, x, y)
+		shape.TexCoords = append(shape.TexCoords, u*texWidth, v*texHeight)
+
+		for _, c := range obj.vao.Colours(vert, vert+1) {
+			white = white && c == 1
+		}
+
+		rotGroup := obj.vao.GroupedRotation(vert)
+		unrotated = unrotated && rotGroup[2] == 1 && rotGroup[3] == 0
+		shape.RotationGroups = append(shape.RotationGroups, sceneRotation(rotGroup))
+	}
+
+	if !white {
+		shape.Colours = append([]float32{}, obj.vao.Colours(index, index+6)...)
+	}
+
+	if unrotated {
+		shape.RotationGroups = nil
+	}
+
+	return shape
+}
+
+// sceneRotation ... convert a rotation as x, y, cos, sin
+func sceneRotation(rot mgl32.Vec4) SceneRotation {
+	return SceneRotation{rot[0], rot[1], float32(math.Atan2(float64(rot[3]), float64(rot[2])))}
+}
+
+func (obj *RenderObject) ownedByParticleSystem() bool {
+	for _, ps := range particleSystems {
+		if &ps.obj == obj {
+			return true
+		}
+	}
+
+	return false
+}
+
+// usesFontAtlas ... whether the render object draws with a TrueType font atlas, which is not a file so cannot be loaded
+func (obj *RenderObject) usesFontAtlas() bool {
+	f, ok := obj.font.(*Font)
+
+	return ok && obj.texture != nil && f.Texture() == obj.texture
+}
+
+/*
+Scene jobs
+*/
+
+func callSaveScene(job RenderObjectJob) {
+	SaveScene(job.params[0].(string))
+}
+
+func callCreateScene(job RenderObjectJob) {
+	*(*[]*RenderObject)(job.retVal) = job.params[0].(*Scene).Create()
+}
+
+func SaveSceneJob(file string) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{file},
+		nil,
+		callSaveScene,
+	}
+}
+
+// CreateJob ... the returned slice is populated once the job has been performed
+func (s *Scene) CreateJob() *[]*RenderObject {
+	objs := make([]*RenderObject, 0)
+
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{s},
+		unsafe.Pointer(&objs),
+		callCreateScene,
+	}
+
+	return &objs
+}
@@ -0,0 +1,176 @@
+package graphics
+
+import (
+	"encoding/json"
+	"gopengl/graphics/opengl"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testScene() *Scene {
+	return &Scene{
+		Version: SceneVersion,
+		Camera:  SceneCamera{X: 10, Y: -20, Zoom: 2, Rotation: 0.5},
+		Objects: []SceneObject{
+			{
+				Texture:    "./sprites/ships.png",
+				Capacity:   24,
+				Layer:      2,
+				TranslateX: 5,
+				Rotation:   SceneRotation{X: 16, Y: 16, Rad: 1},
+				Shapes: []SceneShape{
+					{
+						Verts:     []float32{0, 0, 32, 0, 32, 32, 0, 0, 32, 32, 0, 32},
+						TexCoords: []float32{0, 0, 32, 0, 32, 32, 0, 0, 32, 32, 0, 32},
+					},
+					{
+						Verts:          []float32{32, 0, 64, 0, 64, 32, 32, 0, 64, 32, 32, 32},
+						TexCoords:      []float32{32, 0, 64, 0, 64, 32, 32, 0, 64, 32, 32, 32},
+						Colours:        []float32{1, 0, 0, 1, 1, 0, 0, 1, 1, 0, 0, 1, 1, 0, 0, 1, 1, 0, 0, 1, 1, 0, 0, 1},
+						RotationGroups: make([]SceneRotation, 6),
+						Hidden:         true,
+						TexFlags:       FlipX | Rotate90,
+					},
+				},
+			},
+			{
+				Texture:     "./sprites/hud.png",
+				ScreenSpace: true,
+				Invisible:   true,
+			},
+			{
+				Texture: "./sprites/bullet.png",
+				Instancing: &SceneInstancing{
+					Width:     4,
+					Height:    8,
+					Instances: []Instance{CreateInstance(1, 2)},
+				},
+			},
+		},
+	}
+}
+
+// writeScene ... write data to a scene file in a temporary root directory, returns the file
+func writeScene(t *testing.T, data []byte) string {
+	dir := t.TempDir()
+	t.Setenv("root_file_path", dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "scene.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return "scene.json"
+}
+
+func TestSceneRoundTrip(t *testing.T) {
+	want := testScene()
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := LoadScene(writeScene(t, data))
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestSceneOmitsDefaults(t *testing.T) {
+	data, err := json.Marshal(SceneObject{
+		Texture: "./sprites/hud.png",
+		Shapes:  []SceneShape{{Verts: []float32{0, 0}, TexCoords: []float32{0, 0}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{"capacity", "customShader", "layer", "screenSpace", "invisible", "cullingDisabled",
+		"translateX", "translateY", "instancing", "colours", "rotationGroups", "hidden", "texFlags"} {
+		if strings.Contains(string(data), `"`+field+`"`) {
+			t.Errorf("default %s was written: %s", field, data)
+		}
+	}
+}
+
+func TestSceneSkipsTrueTypeText(t *testing.T) {
+	saved, savedCamera := renderObjects, camera
+	defer func() { renderObjects, camera = saved, savedCamera }()
+
+	atlas := &opengl.Texture{}
+	renderObjects = []*RenderObject{{font: &Font{atlas: atlas}, texture: atlas}}
+	camera = &Camera{x: 10, y: -20, zoom: 2, rot: 0.5}
+
+	t.Setenv("root_file_path", t.TempDir())
+	SaveScene("scene.json")
+
+	camera = CreateCamera()
+	s := LoadScene("scene.json")
+
+	if len(s.Objects) != 0 {
+		t.Errorf("got %d objects, want the text to be skipped", len(s.Objects))
+	}
+
+	if objs := s.Create(); len(objs) != 0 {
+		t.Errorf("created %d objects, want 0", len(objs))
+	}
+
+	if camera.x != 10 || camera.y != -20 || camera.zoom != 2 || camera.rot != 0.5 {
+		t.Errorf("got camera %+v, want the saved camera", *camera)
+	}
+}
+
+func TestLoadScene(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantPanic bool
+	}{
+		{"minimal", `{"version": 1, "objects": [{"texture": "a.png", "shapes": []}]}`, false},
+		{"missing version", `{"objects": []}`, true},
+		{"future version", `{"version": 2}`, true},
+		{"invalid json", `{"version": 1,`, true},
+		{"wrong type", `{"version": "1"}`, true},
+	}
+
+	for _, test := range tests {
+		file := writeScene(t, []byte(test.data))
+
+		if panicked := panics(func() { LoadScene(file) }); panicked != test.wantPanic {
+			t.Errorf("%s: panicked %v, want %v", test.name, panicked, test.wantPanic)
+		}
+	}
+
+	t.Setenv("root_file_path", t.TempDir())
+
+	if !panics(func() { LoadScene("missing.json") }) {
+		t.Error("missing scene did not panic")
+	}
+}
+
+func TestSceneRotation(t *testing.T) {
+	tests := []struct {
+		rad float64
+	}{
+		{0},
+		{1},
+		{-2},
+		{math.Pi / 2},
+	}
+
+	for _, test := range tests {
+		rot := mgl32.Vec4{3, 4, float32(math.Cos(test.rad)), float32(math.Sin(test.rad))}
+		got := sceneRotation(rot)
+
+		if got.X != 3 || got.Y != 4 || math.Abs(float64(got.Rad)-test.rad) > 1e-6 {
+			t.Errorf("%v: got %+v", test.rad, got)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package graphics
+
+import (
+	"unsafe"
+)
+
+/*
+Sprites are handles to a single rectangle within a render object, they keep track of the rectangles
+vertex range so it can be moved, rotated and scaled without the caller managing vertices directly.
+
+Position, scale and origin are composed into the vertex data, rotation is performed about the origin
+using the rectangles grouped rotation.
+*/
+
+type Sprite struct {
+	obj              *RenderObject
+	index            int
+	width, height    float32
+	x, y             float32
+	rot              float32
+	scaleX, scaleY   float32
+	originX, originY float32
+}
+
+// AddSprite ... add a rectangle to the render object and return a handle to it, position is from the top left in pixels
+func (obj *RenderObject) AddSprite(x, y, xTex, yTex, width, height, widthTex, heightTex float32) *Sprite {
+	sprite := &Sprite{obj: obj}
+	obj.initSprite(sprite, x, y, xTex, yTex, width, height, widthTex, heightTex)
+
+	return sprite
+}
+
+func (obj *RenderObject) initSprite(sprite *Sprite, x, y, xTex, yTex, width, height, widthTex, heightTex float32) {
+	sprite.index = obj.AddRect(x, y, xTex, yTex, width, height, widthTex, heightTex)
+	sprite.width = width
+	sprite.height = height
+	sprite.x = x
+	sprite.y = y
+	sprite.scaleX = 1
+	sprite.scaleY = 1
+}
+
+// Index ... index of the sprites first vertex, usable with the render object Modify methods
+func (s *Sprite) Index() int {
+	return s.index
+}
+
+func (s *Sprite) RenderObject() *RenderObject {
+	return s.obj
+}
+
+// SetPosition ... set the position of the sprites origin in pixels
+func (s *Sprite) SetPosition(x, y float32) {
+	s.x = x
+	s.y = y
+	s.apply()
+}
+
+// SetRotation ... set the rotation of the sprite about its origin in radians
+func (s *Sprite) SetRotation(rad float32) {
+	s.rot = rad
+	s.apply()
+}
+
+func (s *Sprite) SetScale(x, y float32) {
+	s.scaleX = x
+	s.scaleY = y
+	s.apply()
+}
+
+// SetOrigin ... set the point the sprite is positioned, rotated and scaled about, in unscaled pixels from the sprites top left
+func (s *Sprite) SetOrigin(x, y float32) {
+	s.originX = x
+	s.originY = y
+	s.apply()
+}
+
+func (s *Sprite) Position() (x, y float32) {
+	return s.x, s.y
+}
+
+func (s *Sprite) Rotation() float32 {
+	return s.rot
+}
+
+func (s *Sprite) Scale() (x, y float32) {
+	return s.scaleX, s.scaleY
+}
+
+func (s *Sprite) Origin() (x, y float32) {
+	return s.originX, s.originY
+}
+
+// apply ... recompute the sprites vertices and grouped rotation then update the buffers
+func (s *Sprite) apply() {
+	left := s.x - s.originX*s.scaleX
+	top := s.y - s.originY*s.scaleY
+	right := left + s.width*s.scaleX
+	bottom := top + s.height*s.scaleY
+
+	verts := []float32{
+		// Upper right triangle
+		left, top,
+		right, top,
+		right, bottom,
+
+		// Lower left triangle
+		left, top,
+		right, bottom,
+		left, bottom,
+	}
+
+	s.obj.SetGroupedRotation(s.x, s.y, s.rot, s.index, s.index+6)
+	s.obj.vao.UpdateVertBufferIndex(s.index, verts)
+}
+
+/*
+Sprite jobs, the sprite handle is passed as the first parameter.
+*/
+
+func callAddSprite(job RenderObjectJob) {
+	params := job.params
+
+	job.obj.initSprite(
+		(*Sprite)(job.retVal),
+		params[0].(float32),
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+		params[4].(float32),
+		params[5].(float32),
+		params[6].(float32),
+		params[7].(float32),
+	)
+}
+
+func callSpriteSetPosition(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Sprite).SetPosition(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+func callSpriteSetRotation(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Sprite).SetRotation(params[1].(float32))
+}
+
+func callSpriteSetScale(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Sprite).SetScale(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+func callSpriteSetOrigin(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Sprite).SetOrigin(
+		params[1].(float32),
+		params[2].(float32),
+	)
+}
+
+// AddSpriteJob ... the returned sprite is populated once the job has been performed, its Job methods can be used immediately
+func (obj *RenderObject) AddSpriteJob(x, y, xTex, yTex, width, height, widthTex, heightTex float32) *Sprite {
+	sprite := &Sprite{obj: obj}
+
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{x, y, xTex, yTex, width, height, widthTex, heightTex},
+		unsafe.Pointer(sprite),
+		callAddSprite,
+	}
+
+	return sprite
+}
+
+func (s *Sprite) SetPositionJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		s.obj,
+		[]interface{}{s, x, y},
+		nil,
+		callSpriteSetPosition,
+	}
+}
+
+func (s *Sprite) SetRotationJob(rad float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		s.obj,
+		[]interface{}{s, rad},
+		nil,
+		callSpriteSetRotation,
+	}
+}
+
+func (s *Sprite) SetScaleJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		s.obj,
+		[]interface{}{s, x, y},
+		nil,
+		callSpriteSetScale,
+	}
+}
+
+func (s *Sprite) SetOriginJob(x, y float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		s.obj,
+		[]interface{}{s, x, y},
+		nil,
+		callSpriteSetOrigin,
+	}
+}
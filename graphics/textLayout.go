@@ -0,0 +1,477 @@
+package graphics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Text layout, lays out paragraphs of text wrapped to a width with alignment and inline colour markup.
+Colour spans are written as [colour=#rrggbb]text[/colour] (or #rrggbbaa, color is also accepted), [[ is a literal [.
+
+Layout looks up glyphs in the font which may add them to the atlas, so it must be performed on the main thread.
+*/
+
+type TextAlign int
+
+const (
+	AlignLeft TextAlign = iota
+	AlignCentre
+	AlignRight
+	// AlignJustify ... stretch the spaces of wrapped lines to fill the width, the last line of a paragraph is left aligned
+	AlignJustify
+)
+
+type TextLayout struct {
+	Font TextFont
+	// Size ... pixel height to draw the text at
+	Size float32
+	// Width ... width to wrap the text to in pixels, 0 disables wrapping
+	Width float32
+	Align TextAlign
+	// LineSpacing ... multiplier of the fonts line height
+	LineSpacing float32
+	// Colour ... colour of text outside of colour spans
+	Colour mgl32.Vec4
+}
+
+// TextBlock ... laid out text, positioned relative to the top left of the block
+type TextBlock struct {
+	font          TextFont
+	glyphs        []placedGlyph
+	lines         int
+	width, height float32
+}
+
+type placedGlyph struct {
+	x, y, width, height float32
+	rect                TexRect
+	colour              mgl32.Vec4
+}
+
+type styledRune struct {
+	r      rune
+	colour mgl32.Vec4
+}
+
+// layoutWord ... a run of non space runes and the spaces preceding it
+type layoutWord struct {
+	runes       []styledRune
+	width       float32
+	spaces      int
+	spaceBefore float32
+}
+
+type layoutLine struct {
+	words []layoutWord
+	width float32
+	// indent ... width of the spaces before the first word
+	indent float32
+	// last ... last line of a paragraph, not stretched when justified
+	last bool
+}
+
+func CreateTextLayout(f TextFont, size, width float32) *TextLayout {
+	return &TextLayout{
+		Font:        f,
+		Size:        size,
+		Width:       width,
+		Align:       AlignLeft,
+		LineSpacing: 1,
+		Colour:      mgl32.Vec4{1, 1, 1, 1},
+	}
+}
+
+// Measure ... width and height the text will take up once laid out
+func (layout *TextLayout) Measure(text string) (width, height float32) {
+	return layout.Layout(text).Bounds()
+}
+
+func (layout *TextLayout) Layout(text string) *TextBlock {
+	scale := layout.Size / layout.Font.Size()
+	lineHeight := layout.Font.LineHeight() * scale
+	lineAdvance := lineHeight * layout.LineSpacing
+	spaceWidth := layout.advance(' ', scale)
+
+	lines := make([]layoutLine, 0)
+
+	for _, paragraph := range splitParagraphs(parseMarkup(text, layout.Colour)) {
+		lines = append(lines, layout.wrap(paragraph, scale, spaceWidth)...)
+	}
+
+	block := &TextBlock{
+		font:  layout.Font,
+		lines: len(lines),
+	}
+
+	for _, line := range lines {
+		if line.width > block.width {
+			block.width = line.width
+		}
+	}
+
+	// Alignment is relative to the wrap width, or the widest line if not wrapping
+	alignWidth := layout.Width
+	if alignWidth <= 0 {
+		alignWidth = block.width
+	}
+
+	for i, line := range lines {
+		penX, gap := layout.alignLine(line, alignWidth)
+		penX += line.indent
+		baseline := float32(i)*lineAdvance + layout.Font.Ascent()*scale
+
+		for j, word := range line.words {
+			if j > 0 {
+				penX += word.spaceBefore + gap*float32(word.spaces)
+			}
+
+			block.placeWord(word, penX, baseline, scale)
+			penX += word.width
+		}
+	}
+
+	if len(lines) > 0 {
+		block.height = float32(len(lines)-1)*lineAdvance + lineHeight
+	}
+
+	return block
+}
+
+// Bounds ... width and height of the laid out text
+func (block *TextBlock) Bounds() (width, height float32) {
+	return block.width, block.height
+}
+
+func (block *TextBlock) Lines() int {
+	return block.lines
+}
+
+// VertCount ... number of vertices used when the block is added to a render object
+func (block *TextBlock) VertCount() int {
+	return len(block.glyphs) * 6
+}
+
+// wrap ... break a paragraph into lines no wider than the layout width
+func (layout *TextLayout) wrap(paragraph []styledRune, scale, spaceWidth float32) []layoutLine {
+	words := layout.splitWords(paragraph, scale, spaceWidth)
+	lines := make([]layoutLine, 0, 1)
+
+	// The first line keeps the paragraphs indent
+	line := layoutLine{indent: words[0].spaceBefore, width: words[0].spaceBefore}
+
+	for _, word := range words {
+		width := line.width + word.width
+		if len(line.words) > 0 {
+			width += word.spaceBefore
+		}
+
+		if layout.Width > 0 && len(line.words) > 0 && width > layout.Width {
+			lines = append(lines, line)
+			line = layoutLine{}
+			width = word.width
+		}
+
+		// Words wider than the whole line are broken between runes
+		for layout.Width > 0 && len(line.words) == 0 && line.width+word.width > layout.Width && len(word.runes) > 1 {
+			head, tail := layout.breakWord(word, layout.Width-line.width, scale)
+
+			line.words = append(line.words, head)
+			line.width += head.width
+			lines = append(lines, line)
+
+			line = layoutLine{}
+			word = tail
+			width = word.width
+		}
+
+		line.words = append(line.words, word)
+		line.width = width
+	}
+
+	line.last = true
+
+	return append(lines, line)
+}
+
+// splitWords ... split a paragraph on spaces, spaces before the first word are kept as its indent
+func (layout *TextLayout) splitWords(paragraph []styledRune, scale, spaceWidth float32) []layoutWord {
+	words := make([]layoutWord, 0)
+	word := layoutWord{}
+
+	for _, sr := range paragraph {
+		if unicode.IsSpace(sr.r) {
+			if len(word.runes) > 0 {
+				words = append(words, word)
+				word = layoutWord{}
+			}
+
+			word.spaces++
+			word.spaceBefore += spaceWidth
+
+			continue
+		}
+
+		word.runes = append(word.runes, sr)
+	}
+
+	// Trailing spaces are dropped, empty paragraphs still need a line
+	if len(word.runes) > 0 || len(words) == 0 {
+		words = append(words, word)
+	}
+
+	for i := range words {
+		words[i].width = layout.measureRunes(words[i].runes, scale)
+	}
+
+	return words
+}
+
+// breakWord ... split a word so the first part fits within width, the first part always has at least one rune
+func (layout *TextLayout) breakWord(word layoutWord, width, scale float32) (layoutWord, layoutWord) {
+	split := 1
+
+	for split < len(word.runes) && layout.measureRunes(word.runes[:split+1], scale) <= width {
+		split++
+	}
+
+	head := layoutWord{runes: word.runes[:split], spaces: word.spaces, spaceBefore: word.spaceBefore}
+	tail := layoutWord{runes: word.runes[split:]}
+	head.width = layout.measureRunes(head.runes, scale)
+	tail.width = layout.measureRunes(tail.runes, scale)
+
+	return head, tail
+}
+
+func (layout *TextLayout) measureRunes(runes []styledRune, scale float32) float32 {
+	width := float32(0)
+
+	for i, sr := range runes {
+		if i > 0 {
+			width += layout.Font.kern(runes[i-1].r, sr.r) * scale
+		}
+
+		width += layout.advance(sr.r, scale)
+	}
+
+	return width
+}
+
+func (layout *TextLayout) advance(r rune, scale float32) float32 {
+	g, ok := layout.Font.glyph(r)
+	if !ok {
+		g, _ = layout.Font.glyph(unicode.ReplacementChar)
+	}
+
+	return g.advance * scale
+}
+
+// alignLine ... starting pen position and extra width added to each space
+func (layout *TextLayout) alignLine(line layoutLine, width float32) (penX, gap float32) {
+	switch layout.Align {
+	case AlignCentre:
+		return (width - line.width) / 2, 0
+	case AlignRight:
+		return width - line.width, 0
+	case AlignJustify:
+		spaces := 0
+
+		for _, word := range line.words[1:] {
+			spaces += word.spaces
+		}
+
+		if line.last || spaces == 0 {
+			return 0, 0
+		}
+
+		return 0, (width - line.width) / float32(spaces)
+	}
+
+	return 0, 0
+}
+
+func (block *TextBlock) placeWord(word layoutWord, penX, baseline, scale float32) {
+	for i, sr := range word.runes {
+		g, ok := block.font.glyph(sr.r)
+		if !ok {
+			if g, ok = block.font.glyph(unicode.ReplacementChar); !ok {
+				continue
+			}
+		}
+
+		if i > 0 {
+			penX += block.font.kern(word.runes[i-1].r, sr.r) * scale
+		}
+
+		if g.rect.Width > 0 && g.rect.Height > 0 {
+			block.glyphs = append(block.glyphs, placedGlyph{
+				penX + g.offsetX*scale,
+				baseline + g.offsetY*scale,
+				g.rect.Width * scale,
+				g.rect.Height * scale,
+				g.rect,
+				sr.colour,
+			})
+		}
+
+		penX += g.advance * scale
+	}
+}
+
+/*
+Markup parsing
+*/
+
+// parseMarkup ... convert text to runes with their colour, unrecognised tags are kept as text
+func parseMarkup(text string, colour mgl32.Vec4) []styledRune {
+	runes := make([]styledRune, 0, len(text))
+	colours := []mgl32.Vec4{colour}
+
+	for len(text) > 0 {
+		if strings.HasPrefix(text, "[[") {
+			runes = append(runes, styledRune{'[', colours[len(colours)-1]})
+			text = text[2:]
+
+			continue
+		}
+
+		if end := strings.IndexByte(text, ']'); text[0] == '[' && end != -1 {
+			if c, isTag := parseColourTag(text[1:end]); isTag {
+				if c == nil && len(colours) > 1 {
+					colours = colours[:len(colours)-1]
+				} else if c != nil {
+					colours = append(colours, *c)
+				}
+
+				text = text[end+1:]
+
+				continue
+			}
+		}
+
+		// Invalid UTF-8 decodes as unicode.ReplacementChar with a size of 1
+		r, size := utf8.DecodeRuneInString(text)
+		runes = append(runes, styledRune{r, colours[len(colours)-1]})
+		text = text[size:]
+	}
+
+	return runes
+}
+
+// parseColourTag ... returns the colour of an opening tag, nil for a closing tag and false if it is not a valid tag
+func parseColourTag(tag string) (*mgl32.Vec4, bool) {
+	if tag == "/colour" || tag == "/color" {
+		return nil, true
+	}
+
+	for _, prefix := range []string{"colour=", "color="} {
+		if strings.HasPrefix(tag, prefix) {
+			c, err := ParseColour(tag[len(prefix):])
+
+			return &c, err == nil
+		}
+	}
+
+	return nil, false
+}
+
+func splitParagraphs(runes []styledRune) [][]styledRune {
+	paragraphs := make([][]styledRune, 0, 1)
+	start := 0
+
+	for i, sr := range runes {
+		if sr.r == '\n' {
+			paragraphs = append(paragraphs, runes[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(paragraphs, runes[start:])
+}
+
+// ParseColour ... parse a #rrggbb or #rrggbbaa hex colour
+func ParseColour(hex string) (mgl32.Vec4, error) {
+	hex = strings.TrimPrefix(hex, "#")
+
+	if len(hex) != 6 && len(hex) != 8 {
+		return mgl32.Vec4{}, fmt.Errorf("invalid colour %q", hex)
+	}
+
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return mgl32.Vec4{}, fmt.Errorf("invalid colour %q", hex)
+	}
+
+	return mgl32.Vec4{
+		float32(value>>24&0xff) / 255,
+		float32(value>>16&0xff) / 255,
+		float32(value>>8&0xff) / 255,
+		float32(value&0xff) / 255,
+	}, nil
+}
+
+/*
+Drawing laid out text
+*/
+
+// AddTextBlock ... add laid out text with its top left at x, y, returns the index of the first vertex
+func (obj *RenderObject) AddTextBlock(x, y float32, block *TextBlock) int {
+	if obj.font != block.font {
+		panic("Text block was laid out with a different font to the render object")
+	}
+
+	start := obj.freeVert
+
+	for _, g := range block.glyphs {
+		index := obj.AddRect(x+g.x, y+g.y, g.rect.X, g.rect.Y, g.width, g.height, g.rect.Width, g.rect.Height)
+		obj.SetColour(index, 6, g.colour)
+	}
+
+	return start
+}
+
+// AddTextLayout ... lay out text and add it with its top left at x, y, returns the index of the first vertex
+func (obj *RenderObject) AddTextLayout(x, y float32, text string, layout *TextLayout) int {
+	return obj.AddTextBlock(x, y, layout.Layout(text))
+}
+
+/*
+Text layout jobs
+*/
+
+func callAddTextLayout(job RenderObjectJob) {
+	params := job.params
+
+	index := job.obj.AddTextLayout(
+		params[0].(float32),
+		params[1].(float32),
+		params[2].(string),
+		params[3].(*TextLayout),
+	)
+
+	*(*int)(job.retVal) = index
+}
+
+// AddTextLayoutJob ... the text is laid out when the job is performed, layout must not be modified until then
+func (obj *RenderObject) AddTextLayoutJob(x, y float32, text string, layout *TextLayout) *int {
+	index := 0
+
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{x, y, text, layout},
+		unsafe.Pointer(&index),
+		callAddTextLayout,
+	}
+
+	return &index
+}
@@ -0,0 +1,181 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+	"reflect"
+	"testing"
+	"unicode"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// testFont ... a monospaced font 10 pixels high, every glyph is 10 pixels wide except that A V is kerned together
+type testFont struct{}
+
+func (testFont) Texture() *opengl.Texture { return nil }
+func (testFont) Size() float32            { return 10 }
+func (testFont) LineHeight() float32      { return 12 }
+func (testFont) Ascent() float32          { return 8 }
+
+func (testFont) glyph(r rune) (glyph, bool) {
+	if unicode.IsSpace(r) {
+		return glyph{advance: 10}, true
+	}
+
+	return glyph{rect: TexRect{0, 0, 10, 10}, advance: 10}, true
+}
+
+func (testFont) kern(r0, r1 rune) float32 {
+	if r0 == 'A' && r1 == 'V' {
+		return -2
+	}
+
+	return 0
+}
+
+var (
+	white = mgl32.Vec4{1, 1, 1, 1}
+	red   = mgl32.Vec4{1, 0, 0, 1}
+	green = mgl32.Vec4{0, 1, 0, 1}
+)
+
+func TestParseMarkup(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    string
+		colours []mgl32.Vec4
+	}{
+		{"ab", "ab", []mgl32.Vec4{white, white}},
+		{"a[colour=#ff0000]b[/colour]c", "abc", []mgl32.Vec4{white, red, white}},
+		{"[color=#ff0000]a[/color]", "a", []mgl32.Vec4{red}},
+		{"[colour=#ff0000]a[colour=#00ff00]b[/colour]c", "abc", []mgl32.Vec4{red, green, red}},
+		{"a[/colour]b", "ab", []mgl32.Vec4{white, white}},
+		{"[[colour]", "[colour]", []mgl32.Vec4{white, white, white, white, white, white, white, white}},
+		{"[b]", "[b]", []mgl32.Vec4{white, white, white}},
+		{"[colour=#zz0000]", "[colour=#zz0000]", nil},
+		{"a[", "a[", []mgl32.Vec4{white, white}},
+		{"é", "é", []mgl32.Vec4{white}},
+	}
+
+	for _, test := range tests {
+		runes := parseMarkup(test.text, white)
+		got := make([]rune, len(runes))
+		colours := make([]mgl32.Vec4, len(runes))
+
+		for i, sr := range runes {
+			got[i], colours[i] = sr.r, sr.colour
+		}
+
+		if string(got) != test.want {
+			t.Errorf("%q: got %q, want %q", test.text, string(got), test.want)
+		}
+
+		if test.colours != nil && !reflect.DeepEqual(colours, test.colours) {
+			t.Errorf("%q: got colours %v, want %v", test.text, colours, test.colours)
+		}
+	}
+}
+
+func TestParseColour(t *testing.T) {
+	tests := []struct {
+		hex     string
+		want    mgl32.Vec4
+		wantErr bool
+	}{
+		{"#ff0000", red, false},
+		{"00ff00", green, false},
+		{"#ffffff00", mgl32.Vec4{1, 1, 1, 0}, false},
+		{"#fff", mgl32.Vec4{}, true},
+		{"#gg0000", mgl32.Vec4{}, true},
+		{"", mgl32.Vec4{}, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseColour(test.hex)
+
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: error %v, want error %v", test.hex, err, test.wantErr)
+		}
+
+		if got != test.want {
+			t.Errorf("%q: got %v, want %v", test.hex, got, test.want)
+		}
+	}
+}
+
+func TestLayoutBounds(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		size, width   float32
+		lineSpacing   float32
+		lines         int
+		wantW, wantH  float32
+		wantVertCount int
+	}{
+		{"empty", "", 10, 0, 1, 1, 0, 12, 0},
+		{"single line", "aaa bbb ccc", 10, 0, 1, 1, 110, 12, 54},
+		{"wrapped", "aaa bbb ccc", 10, 50, 1, 3, 30, 36, 54},
+		{"paragraphs", "a\nb", 10, 0, 1, 2, 10, 24, 12},
+		{"empty paragraph", "a\n\nb", 10, 0, 1, 3, 10, 36, 12},
+		{"line spacing", "a\nb", 10, 0, 2, 2, 10, 36, 12},
+		{"broken word", "aaaaaaaa", 10, 50, 1, 2, 50, 24, 48},
+		{"scaled", "ab", 20, 0, 1, 1, 40, 24, 12},
+		{"kerned", "AV", 10, 0, 1, 1, 18, 12, 12},
+		{"trailing spaces", "ab   ", 10, 0, 1, 1, 20, 12, 12},
+		{"markup not measured", "[colour=#ff0000]ab[/colour]", 10, 0, 1, 1, 20, 12, 12},
+	}
+
+	for _, test := range tests {
+		layout := CreateTextLayout(testFont{}, test.size, test.width)
+		layout.LineSpacing = test.lineSpacing
+		block := layout.Layout(test.text)
+		width, height := block.Bounds()
+
+		if block.Lines() != test.lines {
+			t.Errorf("%s: got %d lines, want %d", test.name, block.Lines(), test.lines)
+		}
+
+		if width != test.wantW || height != test.wantH {
+			t.Errorf("%s: got %v x %v, want %v x %v", test.name, width, height, test.wantW, test.wantH)
+		}
+
+		if block.VertCount() != test.wantVertCount {
+			t.Errorf("%s: got %d vertices, want %d", test.name, block.VertCount(), test.wantVertCount)
+		}
+	}
+}
+
+func TestLayoutGlyphPositions(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width float32
+		align TextAlign
+		want  [][2]float32
+	}{
+		{"left", "a b", 0, AlignLeft, [][2]float32{{0, 8}, {20, 8}}},
+		{"indent", "  a", 0, AlignLeft, [][2]float32{{20, 8}}},
+		{"centre", "aa", 50, AlignCentre, [][2]float32{{15, 8}, {25, 8}}},
+		{"right", "aa", 50, AlignRight, [][2]float32{{30, 8}, {40, 8}}},
+		{"centre without wrapping", "aaaa\naa", 0, AlignCentre, [][2]float32{{0, 8}, {10, 8}, {20, 8}, {30, 8}, {10, 20}, {20, 20}}},
+		{"justify", "aa b cc dd", 75, AlignJustify, [][2]float32{{0, 8}, {10, 8}, {32.5, 8}, {55, 8}, {65, 8}, {0, 20}, {10, 20}}},
+		{"justify last line", "aa b", 75, AlignJustify, [][2]float32{{0, 8}, {10, 8}, {30, 8}}},
+		{"kerned", "AV", 0, AlignLeft, [][2]float32{{0, 8}, {8, 8}}},
+	}
+
+	for _, test := range tests {
+		layout := CreateTextLayout(testFont{}, 10, test.width)
+		layout.Align = test.align
+		block := layout.Layout(test.text)
+
+		got := make([][2]float32, len(block.glyphs))
+		for i, g := range block.glyphs {
+			got[i] = [2]float32{g.x, g.y}
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,559 @@
+package graphics
+
+import (
+	"Gopengl/util"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+/*
+Tiled map importing, both .tmx and .json maps are supported along with external .tsx and .json tilesets.
+Only finite orthogonal tile layers are imported, object and image layers are ignored.
+*/
+
+// Tiled stores flip flags in the top bits of each global tile id
+const (
+	tiledFlipHorizontal = 0x80000000
+	tiledFlipVertical   = 0x40000000
+	tiledFlipDiagonal   = 0x20000000
+	tiledFlagMask       = 0xf0000000
+)
+
+type TiledMap struct {
+	Width, Height         int
+	TileWidth, TileHeight int
+	Tilesets              []TiledTileset
+	Layers                []TiledLayer
+}
+
+type TiledTileset struct {
+	*Tileset
+	FirstGID int
+}
+
+type TiledLayer struct {
+	Name string
+	// Tiles ... global tile ids including flip flags, row by row
+	Tiles []uint32
+}
+
+// LoadTiledMap ... load a .tmx or .json map, this only reads the map so can be used from any go routine
+func LoadTiledMap(file string) *TiledMap {
+	data, err := ioutil.ReadFile(util.RelativePath(file))
+	if err != nil {
+		panic(fmt.Errorf("map %q not found on disk: %v", file, err))
+	}
+
+	var m *TiledMap
+
+	if strings.EqualFold(path.Ext(file), ".tmx") {
+		m, err = parseTMX(data, path.Dir(file))
+	} else {
+		m, err = parseTiledJSON(data, path.Dir(file))
+	}
+
+	if err != nil {
+		panic(fmt.Errorf("Tiled map error in %q, error: %v", file, err))
+	}
+
+	return m
+}
+
+/*
+CreateTileMaps ... create the tile maps of every layer with its top left at x, y.
+Render objects can only use one texture so a tile map is created for each tileset used by a layer, they are returned
+in layer order.
+*/
+func (m *TiledMap) CreateTileMaps(x, y float32, chunkSize int) []*TileMap {
+	tileMaps := make([]*TileMap, 0, len(m.Layers))
+
+	for _, layer := range m.Layers {
+		for i, tileset := range m.Tilesets {
+			var tm *TileMap
+
+			for index, gid := range layer.Tiles {
+				id, flip := gid&^tiledFlagMask, tiledFlip(gid)
+
+				if id == 0 || int(id) < tileset.FirstGID || (i+1 < len(m.Tilesets) && int(id) >= m.Tilesets[i+1].FirstGID) {
+					continue
+				}
+
+				if tm == nil {
+					tm = CreateTileMap(
+						tileset.Tileset,
+						m.Width,
+						m.Height,
+						x,
+						y,
+						float32(m.TileWidth),
+						float32(m.TileHeight),
+						chunkSize,
+					)
+				}
+
+				tm.SetTileFlipped(index%m.Width, index/m.Width, int(id)-tileset.FirstGID, flip)
+			}
+
+			if tm != nil {
+				tileMaps = append(tileMaps, tm)
+			}
+		}
+	}
+
+	return tileMaps
+}
+
+func tiledFlip(gid uint32) TileFlip {
+	var flip TileFlip
+
+	if gid&tiledFlipHorizontal != 0 {
+		flip |= FlipHorizontal
+	}
+
+	if gid&tiledFlipVertical != 0 {
+		flip |= FlipVertical
+	}
+
+	if gid&tiledFlipDiagonal != 0 {
+		flip |= FlipDiagonal
+	}
+
+	return flip
+}
+
+/*
+TMX parsing
+*/
+
+type tmxMap struct {
+	Width       int          `xml:"width,attr"`
+	Height      int          `xml:"height,attr"`
+	TileWidth   int          `xml:"tilewidth,attr"`
+	TileHeight  int          `xml:"tileheight,attr"`
+	Orientation string       `xml:"orientation,attr"`
+	Infinite    int          `xml:"infinite,attr"`
+	Tilesets    []tmxTileset `xml:"tileset"`
+	Layers      []tmxLayer   `xml:"layer"`
+}
+
+type tmxTileset struct {
+	FirstGID   int    `xml:"firstgid,attr"`
+	Source     string `xml:"source,attr"`
+	TileWidth  int    `xml:"tilewidth,attr"`
+	TileHeight int    `xml:"tileheight,attr"`
+	Spacing    int    `xml:"spacing,attr"`
+	Margin     int    `xml:"margin,attr"`
+	Columns    int    `xml:"columns,attr"`
+	Image      struct {
+		Source string `xml:"source,attr"`
+	} `xml:"image"`
+	Tiles []struct {
+		ID         int           `xml:"id,attr"`
+		Properties []tmxProperty `xml:"properties>property"`
+	} `xml:"tile"`
+}
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	// Text ... multiline string properties are stored as the element text
+	Text string `xml:",chardata"`
+}
+
+type tmxLayer struct {
+	Name string `xml:"name,attr"`
+	Data struct {
+		Encoding    string `xml:"encoding,attr"`
+		Compression string `xml:"compression,attr"`
+		Text        string `xml:",chardata"`
+		Tiles       []struct {
+			GID uint32 `xml:"gid,attr"`
+		} `xml:"tile"`
+	} `xml:"data"`
+}
+
+func parseTMX(data []byte, dir string) (*TiledMap, error) {
+	var tmx tmxMap
+
+	if err := xml.Unmarshal(data, &tmx); err != nil {
+		return nil, err
+	}
+
+	if err := checkTiledMap(tmx.Orientation, tmx.Infinite != 0); err != nil {
+		return nil, err
+	}
+
+	m := &TiledMap{
+		tmx.Width,
+		tmx.Height,
+		tmx.TileWidth,
+		tmx.TileHeight,
+		make([]TiledTileset, 0, len(tmx.Tilesets)),
+		make([]TiledLayer, 0, len(tmx.Layers)),
+	}
+
+	for _, ts := range tmx.Tilesets {
+		tileset, err := loadTMXTileset(ts, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		m.Tilesets = append(m.Tilesets, TiledTileset{tileset, ts.FirstGID})
+	}
+
+	for _, layer := range tmx.Layers {
+		var tiles []uint32
+		var err error
+
+		switch layer.Data.Encoding {
+		case "":
+			tiles = make([]uint32, len(layer.Data.Tiles))
+
+			for i, tile := range layer.Data.Tiles {
+				tiles[i] = tile.GID
+			}
+		case "csv":
+			tiles, err = decodeTiledCSV(layer.Data.Text)
+		case "base64":
+			tiles, err = decodeTiledBase64(layer.Data.Text, layer.Data.Compression)
+		default:
+			err = fmt.Errorf("unsupported encoding %q", layer.Data.Encoding)
+		}
+
+		if err == nil && len(tiles) != m.Width*m.Height {
+			err = fmt.Errorf("has %d tiles, expected %d", len(tiles), m.Width*m.Height)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("layer %q %v", layer.Name, err)
+		}
+
+		m.Layers = append(m.Layers, TiledLayer{layer.Name, tiles})
+	}
+
+	return m, nil
+}
+
+// loadTMXTileset ... load an embedded or external tileset, paths are relative to the file they are defined in
+func loadTMXTileset(ts tmxTileset, dir string) (*Tileset, error) {
+	if ts.Source != "" {
+		if strings.EqualFold(path.Ext(ts.Source), ".json") || strings.EqualFold(path.Ext(ts.Source), ".tsj") {
+			return loadJSONTilesetFile(path.Join(dir, ts.Source))
+		}
+
+		file := path.Join(dir, ts.Source)
+		data, err := ioutil.ReadFile(util.RelativePath(file))
+		if err != nil {
+			return nil, err
+		}
+
+		external := tmxTileset{}
+		if err := xml.Unmarshal(data, &external); err != nil {
+			return nil, fmt.Errorf("tileset %q %v", file, err)
+		}
+
+		ts, dir = external, path.Dir(file)
+	}
+
+	tileset := &Tileset{
+		Texture:    path.Join(dir, ts.Image.Source),
+		TileWidth:  ts.TileWidth,
+		TileHeight: ts.TileHeight,
+		Columns:    ts.Columns,
+		Margin:     ts.Margin,
+		Spacing:    ts.Spacing,
+		Properties: make(map[int]map[string]string),
+	}
+
+	for _, tile := range ts.Tiles {
+		if len(tile.Properties) == 0 {
+			continue
+		}
+
+		properties := make(map[string]string, len(tile.Properties))
+
+		for _, property := range tile.Properties {
+			value := property.Value
+			if value == "" {
+				value = property.Text
+			}
+
+			properties[property.Name] = value
+		}
+
+		tileset.Properties[tile.ID] = properties
+	}
+
+	return tileset, nil
+}
+
+/*
+JSON parsing
+*/
+
+type tiledJSONMap struct {
+	Width       int                `json:"width"`
+	Height      int                `json:"height"`
+	TileWidth   int                `json:"tilewidth"`
+	TileHeight  int                `json:"tileheight"`
+	Orientation string             `json:"orientation"`
+	Infinite    bool               `json:"infinite"`
+	Tilesets    []tiledJSONTileset `json:"tilesets"`
+	Layers      []tiledJSONLayer   `json:"layers"`
+}
+
+type tiledJSONTileset struct {
+	FirstGID   int    `json:"firstgid"`
+	Source     string `json:"source"`
+	Image      string `json:"image"`
+	TileWidth  int    `json:"tilewidth"`
+	TileHeight int    `json:"tileheight"`
+	Spacing    int    `json:"spacing"`
+	Margin     int    `json:"margin"`
+	Columns    int    `json:"columns"`
+	Tiles      []struct {
+		ID         int `json:"id"`
+		Properties []struct {
+			Name  string      `json:"name"`
+			Value interface{} `json:"value"`
+		} `json:"properties"`
+	} `json:"tiles"`
+}
+
+type tiledJSONLayer struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Data        json.RawMessage `json:"data"`
+}
+
+func parseTiledJSON(data []byte, dir string) (*TiledMap, error) {
+	var tj tiledJSONMap
+
+	if err := json.Unmarshal(data, &tj); err != nil {
+		return nil, err
+	}
+
+	if err := checkTiledMap(tj.Orientation, tj.Infinite); err != nil {
+		return nil, err
+	}
+
+	m := &TiledMap{
+		tj.Width,
+		tj.Height,
+		tj.TileWidth,
+		tj.TileHeight,
+		make([]TiledTileset, 0, len(tj.Tilesets)),
+		make([]TiledLayer, 0, len(tj.Layers)),
+	}
+
+	for _, ts := range tj.Tilesets {
+		var tileset *Tileset
+		var err error
+
+		if ts.Source != "" {
+			if strings.EqualFold(path.Ext(ts.Source), ".tsx") {
+				tileset, err = loadTMXTileset(tmxTileset{Source: ts.Source}, dir)
+			} else {
+				tileset, err = loadJSONTilesetFile(path.Join(dir, ts.Source))
+			}
+		} else {
+			tileset = jsonTileset(ts, dir)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		m.Tilesets = append(m.Tilesets, TiledTileset{tileset, ts.FirstGID})
+	}
+
+	for _, layer := range tj.Layers {
+		if layer.Type != "tilelayer" {
+			continue
+		}
+
+		var tiles []uint32
+		var err error
+
+		if layer.Encoding == "base64" {
+			var encoded string
+
+			if err = json.Unmarshal(layer.Data, &encoded); err == nil {
+				tiles, err = decodeTiledBase64(encoded, layer.Compression)
+			}
+		} else {
+			err = json.Unmarshal(layer.Data, &tiles)
+		}
+
+		if err == nil && len(tiles) != m.Width*m.Height {
+			err = fmt.Errorf("has %d tiles, expected %d", len(tiles), m.Width*m.Height)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("layer %q %v", layer.Name, err)
+		}
+
+		m.Layers = append(m.Layers, TiledLayer{layer.Name, tiles})
+	}
+
+	return m, nil
+}
+
+func loadJSONTilesetFile(file string) (*Tileset, error) {
+	data, err := ioutil.ReadFile(util.RelativePath(file))
+	if err != nil {
+		return nil, err
+	}
+
+	var ts tiledJSONTileset
+
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return nil, fmt.Errorf("tileset %q %v", file, err)
+	}
+
+	return jsonTileset(ts, path.Dir(file)), nil
+}
+
+func jsonTileset(ts tiledJSONTileset, dir string) *Tileset {
+	tileset := &Tileset{
+		Texture:    path.Join(dir, ts.Image),
+		TileWidth:  ts.TileWidth,
+		TileHeight: ts.TileHeight,
+		Columns:    ts.Columns,
+		Margin:     ts.Margin,
+		Spacing:    ts.Spacing,
+		Properties: make(map[int]map[string]string),
+	}
+
+	for _, tile := range ts.Tiles {
+		if len(tile.Properties) == 0 {
+			continue
+		}
+
+		properties := make(map[string]string, len(tile.Properties))
+
+		for _, property := range tile.Properties {
+			properties[property.Name] = fmt.Sprint(property.Value)
+		}
+
+		tileset.Properties[tile.ID] = properties
+	}
+
+	return tileset
+}
+
+/*
+Layer data decoding
+*/
+
+func checkTiledMap(orientation string, infinite bool) error {
+	if orientation != "" && orientation != "orthogonal" {
+		return fmt.Errorf("unsupported orientation %q", orientation)
+	}
+
+	if infinite {
+		return fmt.Errorf("infinite maps are not supported")
+	}
+
+	return nil
+}
+
+func decodeTiledCSV(text string) ([]uint32, error) {
+	fields := strings.Split(strings.TrimSpace(text), ",")
+	tiles := make([]uint32, len(fields))
+
+	for i, field := range fields {
+		gid, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		tiles[i] = uint32(gid)
+	}
+
+	return tiles, nil
+}
+
+// decodeTiledBase64 ... tiles are stored as little endian uint32s, optionally compressed
+func decodeTiledBase64(text, compression string) ([]uint32, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader = bytes.NewReader(data)
+
+	switch compression {
+	case "":
+	case "zlib":
+		reader, err = zlib.NewReader(reader)
+	case "gzip":
+		reader, err = gzip.NewReader(reader)
+	default:
+		err = fmt.Errorf("unsupported compression %q", compression)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = ioutil.ReadAll(reader); err != nil {
+		return nil, err
+	}
+
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("tile data length %d is not a multiple of 4", len(data))
+	}
+
+	tiles := make([]uint32, len(data)/4)
+
+	for i := range tiles {
+		tiles[i] = binary.LittleEndian.Uint32(data[i*4:])
+	}
+
+	return tiles, nil
+}
+
+/*
+Tiled jobs
+*/
+
+func callCreateTileMaps(job RenderObjectJob) {
+	params := job.params
+
+	tileMaps := params[0].(*TiledMap).CreateTileMaps(
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(int),
+	)
+
+	*(*[]*TileMap)(job.retVal) = tileMaps
+}
+
+// CreateTileMapsJob ... the returned slice is populated once the job has been performed
+func (m *TiledMap) CreateTileMapsJob(x, y float32, chunkSize int) *[]*TileMap {
+	tileMaps := make([]*TileMap, 0)
+
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{m, x, y, chunkSize},
+		unsafe.Pointer(&tileMaps),
+		callCreateTileMaps,
+	}
+
+	return &tileMaps
+}
@@ -0,0 +1,107 @@
+package graphics
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestTiledFlip(t *testing.T) {
+	tests := []struct {
+		gid  uint32
+		want TileFlip
+	}{
+		{5, 0},
+		{tiledFlipHorizontal | 5, FlipHorizontal},
+		{tiledFlipVertical | 5, FlipVertical},
+		{tiledFlipDiagonal | 5, FlipDiagonal},
+		{tiledFlipHorizontal | tiledFlipVertical | tiledFlipDiagonal, FlipHorizontal | FlipVertical | FlipDiagonal},
+	}
+
+	for _, test := range tests {
+		if got := tiledFlip(test.gid); got != test.want {
+			t.Errorf("gid %#x: got %v, want %v", test.gid, got, test.want)
+		}
+	}
+}
+
+func TestDecodeTiledCSV(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    []uint32
+		wantErr bool
+	}{
+		{"1,2,3", []uint32{1, 2, 3}, false},
+		{"\n0, 4,\n5,0\n", []uint32{0, 4, 5, 0}, false},
+		{"2147483649", []uint32{tiledFlipHorizontal | 1}, false},
+		{"1,a", nil, true},
+		{"1,,2", nil, true},
+	}
+
+	for _, test := range tests {
+		got, err := decodeTiledCSV(test.text)
+
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: error %v, want error %v", test.text, err, test.wantErr)
+			continue
+		}
+
+		if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestDecodeTiledBase64(t *testing.T) {
+	tiles := []uint32{0, 1, tiledFlipVertical | 7, 300}
+
+	raw := new(bytes.Buffer)
+	binary.Write(raw, binary.LittleEndian, tiles)
+
+	zlibbed := new(bytes.Buffer)
+	zw := zlib.NewWriter(zlibbed)
+	zw.Write(raw.Bytes())
+	zw.Close()
+
+	gzipped := new(bytes.Buffer)
+	gw := gzip.NewWriter(gzipped)
+	gw.Write(raw.Bytes())
+	gw.Close()
+
+	encode := func(data []byte) string {
+		return "\n   " + base64.StdEncoding.EncodeToString(data) + "\n"
+	}
+
+	tests := []struct {
+		name        string
+		text        string
+		compression string
+		want        []uint32
+		wantErr     bool
+	}{
+		{"uncompressed", encode(raw.Bytes()), "", tiles, false},
+		{"zlib", encode(zlibbed.Bytes()), "zlib", tiles, false},
+		{"gzip", encode(gzipped.Bytes()), "gzip", tiles, false},
+		{"unsupported compression", encode(raw.Bytes()), "zstd", nil, true},
+		{"invalid base64", "not base64!", "", nil, true},
+		{"partial tile", encode(raw.Bytes()[:6]), "", nil, true},
+		{"wrong compression", encode(raw.Bytes()), "zlib", nil, true},
+	}
+
+	for _, test := range tests {
+		got, err := decodeTiledBase64(test.text, test.compression)
+
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: error %v, want error %v", test.name, err, test.wantErr)
+			continue
+		}
+
+		if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
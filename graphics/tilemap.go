@@ -0,0 +1,299 @@
+package graphics
+
+import (
+	"fmt"
+	"gopengl/graphics/opengl"
+)
+
+/*
+Tile maps, a grid of tiles from a single tileset split into square chunks of render objects.
+Chunks are only created once they contain a tile, each tile has a fixed quad in its chunk so changing a tile
+only updates that quad.
+*/
+
+// EmptyTile ... tile id of a cell with no tile
+const EmptyTile = -1
+
+// TileFlip ... flip flags matching Tiled, the diagonal flip is performed first
+type TileFlip uint8
+
+const (
+	FlipHorizontal TileFlip = 1 << iota
+	FlipVertical
+	// FlipDiagonal ... swap the x and y axis of the tile, combined with the others this rotates the tile
+	FlipDiagonal
+)
+
+type Tileset struct {
+	Texture string
+	// TileWidth, TileHeight ... size of each tile in the texture in pixels
+	TileWidth, TileHeight int
+	// Columns ... number of tiles in each row of the texture, if 0 it is calculated from the texture width
+	Columns         int
+	Margin, Spacing int
+	// Properties ... per tile properties by tile id
+	Properties map[int]map[string]string
+}
+
+type TileMap struct {
+	tileset               *Tileset
+	width, height         int
+	x, y                  float32
+	tileWidth, tileHeight float32
+	chunkSize             int
+	chunksWide            int
+	// columns ... tiles in each row of the tileset texture, calculated once the first chunk loads the texture
+	columns int
+	chunks  []*RenderObject
+	tiles   []int
+	flips   []TileFlip
+}
+
+/*
+CreateTileMap ... create an empty width x height map with its top left at x, y.
+tileWidth and tileHeight are the size tiles are drawn at, chunkSize is the width and height of each chunk in tiles.
+*/
+func CreateTileMap(tileset *Tileset, width, height int, x, y, tileWidth, tileHeight float32, chunkSize int) *TileMap {
+	if chunkSize <= 0 {
+		panic("Tile map chunk size must be positive")
+	}
+
+	chunksWide := (width + chunkSize - 1) / chunkSize
+	chunksHigh := (height + chunkSize - 1) / chunkSize
+
+	tiles := make([]int, width*height)
+	for i := range tiles {
+		tiles[i] = EmptyTile
+	}
+
+	return &TileMap{
+		tileset,
+		width,
+		height,
+		x,
+		y,
+		tileWidth,
+		tileHeight,
+		chunkSize,
+		chunksWide,
+		tileset.Columns,
+		make([]*RenderObject, chunksWide*chunksHigh),
+		tiles,
+		make([]TileFlip, width*height),
+	}
+}
+
+func (tm *TileMap) Size() (width, height int) {
+	return tm.width, tm.height
+}
+
+func (tm *TileMap) Tileset() *Tileset {
+	return tm.tileset
+}
+
+// Chunks ... render objects of the map, chunks without tiles are nil
+func (tm *TileMap) Chunks() []*RenderObject {
+	return tm.chunks
+}
+
+func (tm *TileMap) Tile(x, y int) (id int, flip TileFlip) {
+	tm.checkBounds(x, y)
+
+	return tm.tiles[y*tm.width+x], tm.flips[y*tm.width+x]
+}
+
+// TileProperties ... properties of the tile at x, y from the tileset, nil if it has none
+func (tm *TileMap) TileProperties(x, y int) map[string]string {
+	id, _ := tm.Tile(x, y)
+
+	if id == EmptyTile || tm.tileset.Properties == nil {
+		return nil
+	}
+
+	return tm.tileset.Properties[id]
+}
+
+func (tm *TileMap) SetTile(x, y, id int) {
+	tm.SetTileFlipped(x, y, id, 0)
+}
+
+// SetTileFlipped ... set the tile at x, y, use EmptyTile to clear it
+func (tm *TileMap) SetTileFlipped(x, y, id int, flip TileFlip) {
+	tm.checkBounds(x, y)
+
+	tm.tiles[y*tm.width+x] = id
+	tm.flips[y*tm.width+x] = flip
+
+	chunk := tm.chunks[(y/tm.chunkSize)*tm.chunksWide+x/tm.chunkSize]
+
+	if chunk == nil {
+		// Empty chunks don't need creating
+		if id == EmptyTile {
+			return
+		}
+
+		chunk = tm.createChunk(x/tm.chunkSize, y/tm.chunkSize)
+	}
+
+	tm.updateTile(chunk, x, y)
+}
+
+// ClearTile ... remove the tile at x, y
+func (tm *TileMap) ClearTile(x, y int) {
+	tm.SetTileFlipped(x, y, EmptyTile, 0)
+}
+
+func (tm *TileMap) checkBounds(x, y int) {
+	if x < 0 || y < 0 || x >= tm.width || y >= tm.height {
+		panic(fmt.Errorf("tile %d, %d outside of %d x %d map", x, y, tm.width, tm.height))
+	}
+}
+
+// createChunk ... create the render object for a chunk with an empty quad for each tile
+func (tm *TileMap) createChunk(chunkX, chunkY int) *RenderObject {
+	chunk := &RenderObject{}
+	CreateRenderObject(chunk, tm.chunkSize*tm.chunkSize*6, tm.tileset.Texture, true)
+
+	if tm.columns == 0 {
+		tm.columns = tm.tileset.columnsIn(chunk.texture)
+	}
+
+	for i := 0; i < tm.chunkSize*tm.chunkSize; i++ {
+		chunk.AddRect(0, 0, 0, 0, 0, 0, 0, 0)
+	}
+
+	tm.chunks[chunkY*tm.chunksWide+chunkX] = chunk
+
+	return chunk
+}
+
+func (tm *TileMap) updateTile(chunk *RenderObject, x, y int) {
+	index := ((y%tm.chunkSize)*tm.chunkSize + x%tm.chunkSize) * 6
+	id := tm.tiles[y*tm.width+x]
+
+	if id == EmptyTile {
+		chunk.ModifyVertRect(index, 0, 0, 0, 0)
+
+		return
+	}
+
+	chunk.ModifyVertRect(
+		index,
+		tm.x+float32(x)*tm.tileWidth,
+		tm.y+float32(y)*tm.tileHeight,
+		tm.tileWidth,
+		tm.tileHeight,
+	)
+
+	rect := tm.tileset.tileRect(id, tm.columns)
+	texs := []float32{
+		// Upper right triangle
+		rect.X, rect.Y,
+		rect.X + rect.Width, rect.Y,
+		rect.X + rect.Width, rect.Y + rect.Height,
+
+		// Lower left triangle
+		rect.X, rect.Y,
+		rect.X + rect.Width, rect.Y + rect.Height,
+		rect.X, rect.Y + rect.Height,
+	}
+
+	texs = flipRectTexs(texs, tm.flips[y*tm.width+x])
+	chunk.vao.UpdateTexBufferIndex(index, chunk.texture.PixToTex(texs))
+}
+
+// tileRect ... texture rectangle of a tile in pixels for a tileset texture with columns tiles in each row
+func (ts *Tileset) tileRect(id, columns int) TexRect {
+	column := id % columns
+	row := id / columns
+
+	return TexRect{
+		float32(ts.Margin + column*(ts.TileWidth+ts.Spacing)),
+		float32(ts.Margin + row*(ts.TileHeight+ts.Spacing)),
+		float32(ts.TileWidth),
+		float32(ts.TileHeight),
+	}
+}
+
+func (ts *Tileset) columnsIn(texture *opengl.Texture) int {
+	columns := (texture.Width() - 2*ts.Margin + ts.Spacing) / (ts.TileWidth + ts.Spacing)
+
+	if columns <= 0 {
+		panic(fmt.Errorf("tileset %q is narrower than a single tile", ts.Texture))
+	}
+
+	return columns
+}
+
+/*
+flipRectTexs ... permute the texture coordinates of a rectangle from AddRect, the corners are in the order
+top left, top right, bottom right, top left, bottom right, bottom left.
+*/
+func flipRectTexs(texs []float32, flip TileFlip) []float32 {
+	if flip == 0 {
+		return texs
+	}
+
+	// Corners in the order top left, top right, bottom right, bottom left
+	corners := [4][2]float32{
+		{texs[0], texs[1]},
+		{texs[2], texs[3]},
+		{texs[4], texs[5]},
+		{texs[10], texs[11]},
+	}
+
+	if flip&FlipDiagonal != 0 {
+		corners[1], corners[3] = corners[3], corners[1]
+	}
+
+	if flip&FlipHorizontal != 0 {
+		corners[0], corners[1] = corners[1], corners[0]
+		corners[2], corners[3] = corners[3], corners[2]
+	}
+
+	if flip&FlipVertical != 0 {
+		corners[0], corners[3] = corners[3], corners[0]
+		corners[1], corners[2] = corners[2], corners[1]
+	}
+
+	flipped := make([]float32, 0, 12)
+
+	for _, corner := range []int{0, 1, 2, 0, 2, 3} {
+		flipped = append(flipped, corners[corner][0], corners[corner][1])
+	}
+
+	return flipped
+}
+
+/*
+Tile map jobs, the tile map is passed as the first parameter.
+*/
+
+func callSetTileFlipped(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*TileMap).SetTileFlipped(
+		params[1].(int),
+		params[2].(int),
+		params[3].(int),
+		params[4].(TileFlip),
+	)
+}
+
+// SetTileJob ... tile maps used with jobs must only be modified using jobs
+func (tm *TileMap) SetTileJob(x, y, id int) {
+	tm.SetTileFlippedJob(x, y, id, 0)
+}
+
+func (tm *TileMap) SetTileFlippedJob(x, y, id int, flip TileFlip) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{tm, x, y, id, flip},
+		nil,
+		callSetTileFlipped,
+	}
+}
+
+func (tm *TileMap) ClearTileJob(x, y int) {
+	tm.SetTileFlippedJob(x, y, EmptyTile, 0)
+}
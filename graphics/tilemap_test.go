@@ -0,0 +1,64 @@
+package graphics
+
+import (
+	"reflect"
+	"testing"
+)
+
+// rectTexs ... texture coordinates of the corners in the order used by AddRect
+func rectTexs(tl, tr, br, bl [2]float32) []float32 {
+	return []float32{
+		tl[0], tl[1],
+		tr[0], tr[1],
+		br[0], br[1],
+		tl[0], tl[1],
+		br[0], br[1],
+		bl[0], bl[1],
+	}
+}
+
+func TestFlipRectTexs(t *testing.T) {
+	tl, tr, br, bl := [2]float32{0, 0}, [2]float32{1, 0}, [2]float32{1, 1}, [2]float32{0, 1}
+
+	tests := []struct {
+		name string
+		flip TileFlip
+		want []float32
+	}{
+		{"none", 0, rectTexs(tl, tr, br, bl)},
+		{"horizontal", FlipHorizontal, rectTexs(tr, tl, bl, br)},
+		{"vertical", FlipVertical, rectTexs(bl, br, tr, tl)},
+		{"diagonal", FlipDiagonal, rectTexs(tl, bl, br, tr)},
+		{"horizontal and vertical", FlipHorizontal | FlipVertical, rectTexs(br, bl, tl, tr)},
+		{"diagonal and horizontal", FlipDiagonal | FlipHorizontal, rectTexs(bl, tl, tr, br)},
+		{"diagonal and vertical", FlipDiagonal | FlipVertical, rectTexs(tr, br, bl, tl)},
+	}
+
+	for _, test := range tests {
+		got := flipRectTexs(rectTexs(tl, tr, br, bl), test.flip)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTileRect(t *testing.T) {
+	ts := &Tileset{TileWidth: 16, TileHeight: 8, Margin: 1, Spacing: 2}
+
+	tests := []struct {
+		id   int
+		want TexRect
+	}{
+		{0, TexRect{1, 1, 16, 8}},
+		{1, TexRect{19, 1, 16, 8}},
+		{3, TexRect{1, 11, 16, 8}},
+		{5, TexRect{37, 11, 16, 8}},
+	}
+
+	for _, test := range tests {
+		if got := ts.tileRect(test.id, 3); got != test.want {
+			t.Errorf("tile %d: got %v, want %v", test.id, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package graphics
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Viewports draw render objects into a rectangle of the window with their own camera, eg for split screen.
+A viewport draws the render objects added to it and those on its layers, if it has neither it draws every render object.
+Viewports are drawn in the order they were created, without any viewports every render object is drawn to the whole window
+with the current camera.
+*/
+
+type Viewport struct {
+	x, y, width, height float32
+	camera              *Camera
+	objects             map[*RenderObject]bool
+	layers              map[int]bool
+}
+
+var viewports []*Viewport
+
+// viewportObjects ... render objects drawn by the viewport being drawn, reused each render
+var viewportObjects []*RenderObject
+
+// drawView ... camera and size of the view currently being drawn
+var drawView = windowView(camera)
+
+// drawRect ... rectangle of the view currently being drawn in view pixels, drawingViewport if it is scissored to a viewport
+var drawRect = rect{0, 0, windowWidth, windowHeight}
+var drawingViewport bool
+
+// CreateViewport ... x, y, width and height are in view pixels from the top left
+func CreateViewport(x, y, width, height float32, c *Camera) *Viewport {
+	vp := &Viewport{}
+	vp.init(x, y, width, height, c)
+
+	return vp
+}
+
+func (vp *Viewport) init(x, y, width, height float32, c *Camera) {
+	vp.camera = c
+	vp.objects = make(map[*RenderObject]bool)
+	vp.layers = make(map[int]bool)
+	vp.SetRect(x, y, width, height)
+
+	viewports = append(viewports, vp)
+}
+
+// Delete ... stop drawing the viewport
+func (vp *Viewport) Delete() {
+	for i, viewport := range viewports {
+		if viewport == vp {
+			viewports = append(viewports[:i], viewports[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// SetRect ... move or resize the viewport, zoom and rotation are about its centre
+func (vp *Viewport) SetRect(x, y, width, height float32) {
+	vp.x, vp.y, vp.width, vp.height = x, y, width, height
+}
+
+func (vp *Viewport) Rect() (x, y, width, height float32) {
+	return vp.x, vp.y, vp.width, vp.height
+}
+
+func (vp *Viewport) Camera() *Camera {
+	return vp.camera
+}
+
+func (vp *Viewport) AddRenderObject(obj *RenderObject) {
+	vp.objects[obj] = true
+}
+
+func (vp *Viewport) RemoveRenderObject(obj *RenderObject) {
+	delete(vp.objects, obj)
+}
+
+// AddLayer ... draw every render object on the layer
+func (vp *Viewport) AddLayer(layer int) {
+	vp.layers[layer] = true
+}
+
+func (vp *Viewport) RemoveLayer(layer int) {
+	delete(vp.layers, layer)
+}
+
+// Contains ... if the view position x, y is inside the viewport
+func (vp *Viewport) Contains(x, y float32) bool {
+	return x >= vp.x && y >= vp.y && x < vp.x+vp.width && y < vp.y+vp.height
+}
+
+// ScreenToWorld ... convert a view position, eg the mouse position, to a world position using the viewports camera
+func (vp *Viewport) ScreenToWorld(x, y float32) (worldX, worldY float32) {
+	return vp.view().viewToWorld(x-vp.x, y-vp.y)
+}
+
+// WorldToScreen ... convert a world position to a view position using the viewports camera
+func (vp *Viewport) WorldToScreen(x, y float32) (screenX, screenY float32) {
+	screenX, screenY = vp.view().worldToView(x, y)
+
+	return screenX + vp.x, screenY + vp.y
+}
+
+func (vp *Viewport) view() view {
+	return view{vp.camera, vp.width, vp.height}
+}
+
+// cameraView ... the first viewport drawing the camera, the whole window if it is not drawn by a viewport
+func cameraView(c *Camera) view {
+	for _, vp := range viewports {
+		if vp.camera == c {
+			return vp.view()
+		}
+	}
+
+	return windowView(c)
+}
+
+func (vp *Viewport) draws(obj *RenderObject) bool {
+	if len(vp.objects) == 0 && len(vp.layers) == 0 {
+		return true
+	}
+
+	return vp.objects[obj] || vp.layers[obj.layer]
+}
+
+/*
+Viewport rendering
+*/
+
+func renderViewports() {
+	if len(viewports) == 0 {
+		drawView = windowView(camera)
+		drawRect = rect{0, 0, windowWidth, windowHeight}
+		gl.Viewport(outputRect(0, 0, windowWidth, windowHeight))
+		drawRenderObjects(renderObjects)
+
+		return
+	}
+
+	gl.Enable(gl.SCISSOR_TEST)
+	drawingViewport = true
+
+	for _, vp := range viewports {
+		x, y, width, height := outputRect(vp.x, vp.y, vp.width, vp.height)
+
+		gl.Viewport(x, y, width, height)
+		gl.Scissor(x, y, width, height)
+		drawView = vp.view()
+		drawRect = rect{vp.x, vp.y, vp.width, vp.height}
+
+		viewportObjects = viewportObjects[:0]
+		for _, obj := range renderObjects {
+			if vp.draws(obj) {
+				viewportObjects = append(viewportObjects, obj)
+			}
+		}
+
+		drawRenderObjects(viewportObjects)
+	}
+
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.Viewport(outputRect(0, 0, windowWidth, windowHeight))
+	drawView = windowView(camera)
+	drawRect = rect{0, 0, windowWidth, windowHeight}
+	drawingViewport = false
+}
+
+/*
+Viewport jobs, the viewport is passed as the first parameter.
+*/
+
+func callCreateViewport(job RenderObjectJob) {
+	params := job.params
+
+	(*Viewport)(job.retVal).init(
+		params[0].(float32),
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+		params[4].(*Camera),
+	)
+}
+
+func callDeleteViewport(job RenderObjectJob) {
+	job.params[0].(*Viewport).Delete()
+}
+
+func callViewportSetRect(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Viewport).SetRect(
+		params[1].(float32),
+		params[2].(float32),
+		params[3].(float32),
+		params[4].(float32),
+	)
+}
+
+func callViewportAddRenderObject(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Viewport).AddRenderObject(params[1].(*RenderObject))
+}
+
+func callViewportRemoveRenderObject(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Viewport).RemoveRenderObject(params[1].(*RenderObject))
+}
+
+func callViewportAddLayer(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Viewport).AddLayer(params[1].(int))
+}
+
+func callViewportRemoveLayer(job RenderObjectJob) {
+	params := job.params
+
+	params[0].(*Viewport).RemoveLayer(params[1].(int))
+}
+
+// CreateViewportJob ... the returned viewport is populated once the job has been performed, its Job methods can be used immediately
+func CreateViewportJob(x, y, width, height float32, c *Camera) *Viewport {
+	vp := &Viewport{}
+
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{x, y, width, height, c},
+		unsafe.Pointer(vp),
+		callCreateViewport,
+	}
+
+	return vp
+}
+
+func (vp *Viewport) DeleteJob() {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{vp},
+		nil,
+		callDeleteViewport,
+	}
+}
+
+func (vp *Viewport) SetRectJob(x, y, width, height float32) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{vp, x, y, width, height},
+		nil,
+		callViewportSetRect,
+	}
+}
+
+func (vp *Viewport) AddRenderObjectJob(obj *RenderObject) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{vp, obj},
+		nil,
+		callViewportAddRenderObject,
+	}
+}
+
+func (vp *Viewport) RemoveRenderObjectJob(obj *RenderObject) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{vp, obj},
+		nil,
+		callViewportRemoveRenderObject,
+	}
+}
+
+func (vp *Viewport) AddLayerJob(layer int) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{vp, layer},
+		nil,
+		callViewportAddLayer,
+	}
+}
+
+func (vp *Viewport) RemoveLayerJob(layer int) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{vp, layer},
+		nil,
+		callViewportRemoveLayer,
+	}
+}
@@ -1,6 +1,9 @@
 package graphics
 
 import (
+	"gopengl/graphics/opengl"
+	"math"
+
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
@@ -10,7 +13,12 @@ func CreateWindow(width, height int, name string) *glfw.Window {
 
 	checkerr(err)
 
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+	if resizable {
+		glfw.WindowHint(glfw.Resizable, glfw.True)
+	} else {
+		glfw.WindowHint(glfw.Resizable, glfw.False)
+	}
+
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
@@ -29,6 +37,138 @@ func Poll(window *glfw.Window) {
 	pollInputs(window)
 }
 
+/*
+Resizing, the view is the size set by SetWindowSize and is fitted to the window using the scale policy.
+Positions, the mouse position and viewports are all in view pixels.
+
+On high DPI displays the framebuffer has more pixels than the window has screen coordinates, the content scale
+is the ratio between them. Drawing uses framebuffer pixels and the cursor uses screen coordinates, both are converted
+to view pixels so the view is the same on any DPI.
+*/
+
+type ScalePolicy int
+
+const (
+	// ScaleStretch ... stretch the view to fill the window
+	ScaleStretch ScalePolicy = iota
+	// ScaleLetterbox ... scale the view to fit the window keeping its aspect ratio, the rest of the window is left clear
+	ScaleLetterbox
+	// ScaleExpand ... resize the view to the window, showing more or less of the world
+	ScaleExpand
+)
+
+var (
+	resizable                         = false
+	scalePolicy                       = ScaleStretch
+	designWidth, designHeight float32 = 800, 600
+	// screenWidth, screenHeight ... size of the window in screen coordinates
+	screenWidth, screenHeight float32 = 800, 600
+	// framebufferWidth, framebufferHeight ... size of the window in pixels
+	framebufferWidth, framebufferHeight float32 = 800, 600
+	// Rectangle of the framebuffer the view is drawn to
+	outputX, outputY, outputWidth, outputHeight float32 = 0, 0, 800, 600
+)
+
+// SetWindowResizable ... windows are a fixed size by default, must be called before CreateWindow
+func SetWindowResizable(isResizable bool) {
+	resizable = isResizable
+}
+
+func SetScalePolicy(policy ScalePolicy) {
+	scalePolicy = policy
+	applyScalePolicy()
+}
+
+func callSetScalePolicy(job RenderObjectJob) {
+	SetScalePolicy(job.params[0].(ScalePolicy))
+}
+
+func SetScalePolicyJob(policy ScalePolicy) {
+	RenderObjectQueue <- RenderObjectJob{
+		nil,
+		[]interface{}{policy},
+		nil,
+		callSetScalePolicy,
+	}
+}
+
+func onFramebufferResize(w *glfw.Window, width, height int) {
+	resizeWindow(w)
+}
+
+func onWindowResize(w *glfw.Window, width, height int) {
+	resizeWindow(w)
+}
+
+// resizeWindow ... update the window and framebuffer sizes from the window
+func resizeWindow(w *glfw.Window) {
+	width, height := w.GetSize()
+	fbWidth, fbHeight := w.GetFramebufferSize()
+
+	// Minimized windows have no size
+	if width == 0 || height == 0 || fbWidth == 0 || fbHeight == 0 {
+		return
+	}
+
+	screenWidth, screenHeight = float32(width), float32(height)
+	framebufferWidth, framebufferHeight = float32(fbWidth), float32(fbHeight)
+	applyScalePolicy()
+}
+
+// applyScalePolicy ... update the view size and the rectangle of the framebuffer it is drawn to
+func applyScalePolicy() {
+	outputX, outputY, outputWidth, outputHeight = 0, 0, framebufferWidth, framebufferHeight
+
+	switch scalePolicy {
+	case ScaleStretch:
+		windowWidth, windowHeight = designWidth, designHeight
+	case ScaleLetterbox:
+		windowWidth, windowHeight = designWidth, designHeight
+		scale := float32(math.Min(float64(framebufferWidth/designWidth), float64(framebufferHeight/designHeight)))
+
+		outputWidth = designWidth * scale
+		outputHeight = designHeight * scale
+		outputX = (framebufferWidth - outputWidth) / 2
+		outputY = (framebufferHeight - outputHeight) / 2
+	case ScaleExpand:
+		// Expand in screen coordinates so the view is the same size on any DPI
+		windowWidth, windowHeight = screenWidth, screenHeight
+	}
+
+	opengl.SetAllDimensions(windowWidth, windowHeight)
+}
+
+// outputRect ... convert a rectangle in view pixels from the top left to framebuffer pixels from the bottom left, as used by opengl
+func outputRect(x, y, width, height float32) (int32, int32, int32, int32) {
+	scaleX := outputWidth / windowWidth
+	scaleY := outputHeight / windowHeight
+
+	return int32(outputX + x*scaleX),
+		int32(framebufferHeight - outputY - (y+height)*scaleY),
+		int32(width * scaleX),
+		int32(height * scaleY)
+}
+
+// ContentScale ... framebuffer pixels per screen coordinate, greater than 1 on high DPI displays
+func ContentScale() (x, y float32) {
+	return framebufferWidth / screenWidth, framebufferHeight / screenHeight
+}
+
+// FramebufferSize ... size of the window in pixels
+func FramebufferSize() (width, height float32) {
+	return framebufferWidth, framebufferHeight
+}
+
+// ScreenSize ... size of the window in screen coordinates, as used by CreateWindow
+func ScreenSize() (width, height float32) {
+	return screenWidth, screenHeight
+}
+
+// ViewSize ... size of the view in pixels
+func ViewSize() (width, height float32) {
+	return windowWidth, windowHeight
+}
+
 /*
 Input handling
 */
@@ -71,7 +211,12 @@ func KeyComboPressed(keys []string) bool {
 func pollMouse(window *glfw.Window) {
 	RButton = window.GetMouseButton(glfw.MouseButtonRight) == glfw.Press
 	LButton = window.GetMouseButton(glfw.MouseButtonLeft) == glfw.Press
-	MouseX, MouseY = window.GetCursorPos()
+	x, y := window.GetCursorPos()
+
+	// Convert from screen coordinates to framebuffer pixels then to view pixels
+	scaleX, scaleY := ContentScale()
+	MouseX = (x*float64(scaleX) - float64(outputX)) * float64(windowWidth/outputWidth)
+	MouseY = (y*float64(scaleY) - float64(outputY)) * float64(windowHeight/outputHeight)
 }
 
 func checkerr(err error) {
@@ -3,6 +3,8 @@ uniform sampler2D tex;
 
 out vec4 frag_colour;
 in vec2 fragtexcoord;
+in vec4 fragcolour;
 void main(){
-    frag_colour=texture(tex, fragtexcoord);
+    // Textures are premultiplied, so premultiply the vertex colour to match
+    frag_colour=texture(tex, fragtexcoord)*vec4(fragcolour.rgb*fragcolour.a,fragcolour.a);
 }
\ No newline at end of file
@@ -0,0 +1,72 @@
+#version 410
+in vec2 vert;
+
+//Per instance position, rotation about the position, scale, texture rect (u, v, width, height) and colour
+in vec2 instpos;
+in float instrot;
+in vec2 instscale;
+in vec4 insttexrect;
+in vec4 instcolour;
+
+//Quad size, translation, window dimension scaling, rotation
+uniform vec2 size;
+uniform vec2 trans;
+uniform vec2 dim;
+uniform vec4 rot;
+
+//Camera position and rotation (x, y, cos, sin) and zoom
+uniform vec4 cam;
+uniform float zoom;
+
+out vec2 fragtexcoord;
+out vec4 fragcolour;
+void main(){
+    // Set tex coords and colour for frag shader
+    fragtexcoord=insttexrect.xy+vert*insttexrect.zw;
+    fragcolour=instcolour;
+    
+    // Scale the quad about its centre
+    vec2 pos=(vert-.5)*size*instscale;
+    
+    //Apply instance rotation first, we want local changes then global changes to each vertex
+    mat2 rotmat=mat2(
+        cos(instrot),sin(instrot),
+        -sin(instrot),cos(instrot)
+    );
+    pos=rotmat*pos;
+    
+    pos+=instpos;
+    
+    // Apply uniform rotation
+    vec2 rotcenter=vec2(rot.x,rot.y);
+    pos=pos-rotcenter;
+    
+    rotmat=mat2(
+        rot.z,rot.w,
+        -rot.w,rot.z
+    );
+    
+    pos=rotmat*pos;
+    
+    pos=pos+rotcenter;
+    
+    // Apply translation
+    pos+=trans;
+    
+    // Apply camera, zoom and rotation are about the centre of the screen
+    vec2 centre=.5*dim;
+    pos=pos-vec2(cam.x,cam.y)-centre;
+    
+    rotmat=mat2(
+        cam.z,-cam.w,
+        cam.w,cam.z
+    );
+    
+    pos=rotmat*pos*zoom+centre;
+    
+    // Apply screen scaling from pixel coordinates
+    pos.x=(pos.x/(.5*dim.x))-1;
+    pos.y=1-(pos.y/(.5*dim.y));
+    
+    gl_Position=vec4(pos,0.,1.);
+}
\ No newline at end of file
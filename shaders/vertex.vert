@@ -2,16 +2,23 @@
 in vec2 vert;
 in vec4 rotgroup;
 in vec2 verttexcoord;
+in vec4 vertcolour;
 
 //Translation, window dimension scaling, rotation
 uniform vec2 trans;
 uniform vec2 dim;
 uniform vec4 rot;
 
+//Camera position and rotation (x, y, cos, sin) and zoom
+uniform vec4 cam;
+uniform float zoom;
+
 out vec2 fragtexcoord;
+out vec4 fragcolour;
 void main(){
-    // Set tex coords for frag shader
+    // Set tex coords and colour for frag shader
     fragtexcoord=verttexcoord;
+    fragcolour=vertcolour;
     vec2 pos=vert;
     
     //Apply rotgroup rotation first, we want local changes then global changes to each vertex
@@ -39,9 +46,23 @@ void main(){
     
     pos=pos+rotcenter;
     
+    // Apply translation
+    pos+=trans;
+    
+    // Apply camera, zoom and rotation are about the centre of the screen
+    vec2 centre=.5*dim;
+    pos=pos-vec2(cam.x,cam.y)-centre;
+    
+    rotmat=mat2(
+        cam.z,-cam.w,
+        cam.w,cam.z
+    );
+    
+    pos=rotmat*pos*zoom+centre;
+    
     // Apply screen scaling from pixel coordinates
     pos.x=(pos.x/(.5*dim.x))-1;
     pos.y=1-(pos.y/(.5*dim.y));
     
-    gl_Position=vec4(pos+trans,0.,1.);
+    gl_Position=vec4(pos,0.,1.);
 }
\ No newline at end of file